@@ -0,0 +1,29 @@
+// Command smolparser is a tiny demo CLI that exercises the smolparser
+// library against a few sample JSON documents.
+package main
+
+import (
+	"fmt"
+
+	smolparser "smol-go/smol-parser"
+)
+
+func main() {
+	testCases := []string{
+		`{"name": "John", "age": 30, "active": true}`,
+		`[1, 2, 3, "hello", null, false]`,
+		`{"user": {"name": "Alice", "scores": [95, 87, 92]}}`,
+		"{\"unicode\": \"Hello \\u0057orld\"}",
+		`{"number": -123.45e-6}`,
+	}
+
+	for i, tc := range testCases {
+		fmt.Printf("\nTest case %d: %s\n", i+1, tc)
+		result, err := smolparser.Parse(tc)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+		} else {
+			fmt.Printf("Result: %+v\n", result)
+		}
+	}
+}
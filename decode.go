@@ -0,0 +1,263 @@
+package smolparser
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// Validator is implemented by types that need to check invariants beyond
+// what field-by-field decoding can express. Decode and DecodeValue call
+// Validate on every struct value they populate (including nested ones)
+// that implements it, after all of that struct's fields have been set.
+type Validator interface {
+	Validate() error
+}
+
+// DecodeOptions controls optional behavior of Decode/DecodeValue beyond
+// plain field-by-field binding.
+type DecodeOptions struct {
+	// TimeLayout is the layout (as accepted by time.Parse) used to parse
+	// string values into time.Time fields. It defaults to time.RFC3339.
+	TimeLayout string
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// Decode parses input and binds the result into v, which must be a
+// non-nil pointer. It is the schema-driven counterpart to Parse: where
+// Parse returns an untyped interface{} tree, Decode populates a Go value
+// using "json" struct tags, following the same conventions as
+// encoding/json.
+func Decode(input string, v interface{}) error {
+	parsed, err := Parse(input)
+	if err != nil {
+		return err
+	}
+	return DecodeValue(parsed, v)
+}
+
+// DecodeValue binds an already-parsed value (as returned by Parse) into
+// v, which must be a non-nil pointer.
+func DecodeValue(parsed interface{}, v interface{}) error {
+	return DecodeValueWithOptions(parsed, v, DecodeOptions{})
+}
+
+// DecodeValueWithOptions is DecodeValue with control over TimeLayout and
+// any other future decode options.
+func DecodeValueWithOptions(parsed interface{}, v interface{}, opts DecodeOptions) error {
+	if opts.TimeLayout == "" {
+		opts.TimeLayout = time.RFC3339
+	}
+	return decodeInto(reflect.ValueOf(v), parsed, opts)
+}
+
+// decodeInto assigns val into the value pointed to by dst, which must be
+// a pointer. It understands "json" struct tags, pointer fields,
+// time.Time, and the Validator interface; stream.go's assignReflect
+// (used by DecodeStream) delegates here so both entry points share the
+// same assignment behavior.
+func decodeInto(dst reflect.Value, val interface{}, opts DecodeOptions) error {
+	if dst.Kind() != reflect.Ptr || dst.IsNil() {
+		return fmt.Errorf("smolparser: Decode target must be a non-nil pointer")
+	}
+	if opts.TimeLayout == "" {
+		opts.TimeLayout = time.RFC3339
+	}
+	elem := dst.Elem()
+
+	if elem.Kind() == reflect.Ptr {
+		if val == nil {
+			elem.Set(reflect.Zero(elem.Type()))
+			return nil
+		}
+		ptr := reflect.New(elem.Type().Elem())
+		if err := decodeInto(ptr, val, opts); err != nil {
+			return err
+		}
+		elem.Set(ptr)
+		return nil
+	}
+
+	if elem.Kind() == reflect.Struct && elem.Type() == timeType {
+		s, ok := val.(string)
+		if !ok {
+			return fmt.Errorf("smolparser: cannot assign %T to time.Time", val)
+		}
+		t, err := time.Parse(opts.TimeLayout, s)
+		if err != nil {
+			return fmt.Errorf("smolparser: parsing time %q: %w", s, err)
+		}
+		elem.Set(reflect.ValueOf(t))
+		return nil
+	}
+
+	switch elem.Kind() {
+	case reflect.Interface:
+		elem.Set(reflect.ValueOf(val))
+		return nil
+
+	case reflect.String:
+		s, ok := val.(string)
+		if !ok {
+			return fmt.Errorf("smolparser: cannot assign %T to string", val)
+		}
+		elem.SetString(s)
+		return nil
+
+	case reflect.Bool:
+		b, ok := val.(bool)
+		if !ok {
+			return fmt.Errorf("smolparser: cannot assign %T to bool", val)
+		}
+		elem.SetBool(b)
+		return nil
+
+	case reflect.Float32, reflect.Float64:
+		n, ok := val.(float64)
+		if !ok {
+			return fmt.Errorf("smolparser: cannot assign %T to %s", val, elem.Kind())
+		}
+		elem.SetFloat(n)
+		return nil
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, ok := val.(float64)
+		if !ok {
+			return fmt.Errorf("smolparser: cannot assign %T to %s", val, elem.Kind())
+		}
+		i := int64(n)
+		if elem.OverflowInt(i) {
+			return fmt.Errorf("smolparser: value %v overflows %s", n, elem.Kind())
+		}
+		elem.SetInt(i)
+		return nil
+
+	case reflect.Slice:
+		if val == nil {
+			elem.Set(reflect.Zero(elem.Type()))
+			return nil
+		}
+		arr, ok := val.([]interface{})
+		if !ok {
+			return fmt.Errorf("smolparser: cannot assign %T to slice", val)
+		}
+		out := reflect.MakeSlice(elem.Type(), len(arr), len(arr))
+		for i, item := range arr {
+			if err := decodeInto(out.Index(i).Addr(), item, opts); err != nil {
+				return err
+			}
+		}
+		elem.Set(out)
+		return nil
+
+	case reflect.Map:
+		if val == nil {
+			elem.Set(reflect.Zero(elem.Type()))
+			return nil
+		}
+		m, ok := val.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("smolparser: cannot assign %T to map", val)
+		}
+		keyType := elem.Type().Key()
+		out := reflect.MakeMapWithSize(elem.Type(), len(m))
+		for k, item := range m {
+			keyVal, err := decodeMapKey(k, keyType)
+			if err != nil {
+				return err
+			}
+			itemPtr := reflect.New(elem.Type().Elem())
+			if err := decodeInto(itemPtr, item, opts); err != nil {
+				return err
+			}
+			out.SetMapIndex(keyVal, itemPtr.Elem())
+		}
+		elem.Set(out)
+		return nil
+
+	case reflect.Struct:
+		m, ok := val.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("smolparser: cannot assign %T to struct", val)
+		}
+		t := elem.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if field.PkgPath != "" {
+				continue // unexported
+			}
+			name, skip := parseJSONTag(field.Tag.Get("json"))
+			if skip {
+				continue
+			}
+			if name == "" {
+				name = field.Name
+			}
+			raw, ok := lookupField(m, name)
+			if !ok {
+				continue
+			}
+			if err := decodeInto(elem.Field(i).Addr(), raw, opts); err != nil {
+				return fmt.Errorf("smolparser: field %s: %w", field.Name, err)
+			}
+		}
+		if v, ok := elem.Addr().Interface().(Validator); ok {
+			if err := v.Validate(); err != nil {
+				return fmt.Errorf("smolparser: validate %s: %w", t.Name(), err)
+			}
+		}
+		return nil
+
+	default:
+		return fmt.Errorf("smolparser: unsupported target kind %s", elem.Kind())
+	}
+}
+
+// decodeMapKey converts a JSON object's string key into a reflect.Value
+// assignable to keyType. JSON object keys are always strings, but Go
+// allows map keys of any string or integer kind (as encoding/json does),
+// so keys destined for a non-string-keyed map are parsed from their
+// decimal representation.
+func decodeMapKey(key string, keyType reflect.Type) (reflect.Value, error) {
+	v := reflect.New(keyType).Elem()
+	switch keyType.Kind() {
+	case reflect.String:
+		v.SetString(key)
+		return v, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(key, 10, 64)
+		if err != nil || v.OverflowInt(n) {
+			return reflect.Value{}, fmt.Errorf("smolparser: map key %q is not a valid %s", key, keyType)
+		}
+		v.SetInt(n)
+		return v, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(key, 10, 64)
+		if err != nil || v.OverflowUint(n) {
+			return reflect.Value{}, fmt.Errorf("smolparser: map key %q is not a valid %s", key, keyType)
+		}
+		v.SetUint(n)
+		return v, nil
+	default:
+		return reflect.Value{}, fmt.Errorf("smolparser: unsupported map key type %s", keyType)
+	}
+}
+
+// parseJSONTag extracts the field name from a "json" struct tag, Go
+// stdlib style: `json:"name,omitempty"` yields "name", and `json:"-"`
+// requests the field be skipped entirely.
+func parseJSONTag(tag string) (name string, skip bool) {
+	if tag == "" {
+		return "", false
+	}
+	for i := 0; i < len(tag); i++ {
+		if tag[i] == ',' {
+			// As in encoding/json, `json:"-,"` names a field "-"
+			// rather than skipping it.
+			return tag[:i], false
+		}
+	}
+	return tag, tag == "-"
+}
@@ -0,0 +1,141 @@
+package smolparser
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+type decodeAddress struct {
+	City string `json:"city"`
+	Zip  string `json:"zip"`
+}
+
+type decodeUser struct {
+	Name      string            `json:"name"`
+	Age       int               `json:"age"`
+	Tags      []string          `json:"tags"`
+	Address   *decodeAddress    `json:"address"`
+	Meta      map[string]string `json:"meta"`
+	CreatedAt time.Time         `json:"created_at"`
+	Secret    string            `json:"-"`
+}
+
+func TestDecodeNestedStruct(t *testing.T) {
+	input := `{
+		"name": "Ada",
+		"age": 36,
+		"tags": ["admin", "staff"],
+		"address": {"city": "London", "zip": "SW1"},
+		"meta": {"team": "core"},
+		"created_at": "2026-01-02T15:04:05Z"
+	}`
+
+	var u decodeUser
+	if err := Decode(input, &u); err != nil {
+		t.Fatalf("Decode error: %v", err)
+	}
+
+	if u.Name != "Ada" || u.Age != 36 {
+		t.Errorf("got name=%q age=%d, want name=Ada age=36", u.Name, u.Age)
+	}
+	if len(u.Tags) != 2 || u.Tags[0] != "admin" || u.Tags[1] != "staff" {
+		t.Errorf("got tags=%v, want [admin staff]", u.Tags)
+	}
+	if u.Address == nil || u.Address.City != "London" || u.Address.Zip != "SW1" {
+		t.Errorf("got address=%+v, want {London SW1}", u.Address)
+	}
+	if u.Meta["team"] != "core" {
+		t.Errorf("got meta=%v, want team=core", u.Meta)
+	}
+	want := time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC)
+	if !u.CreatedAt.Equal(want) {
+		t.Errorf("got created_at=%v, want %v", u.CreatedAt, want)
+	}
+	if u.Secret != "" {
+		t.Errorf("json:\"-\" field should never be populated, got %q", u.Secret)
+	}
+}
+
+func TestDecodeMissingOptionalPointer(t *testing.T) {
+	var u decodeUser
+	if err := Decode(`{"name": "Grace"}`, &u); err != nil {
+		t.Fatalf("Decode error: %v", err)
+	}
+	if u.Address != nil {
+		t.Errorf("got address=%+v, want nil", u.Address)
+	}
+}
+
+func TestDecodeValueWithOptionsCustomTimeLayout(t *testing.T) {
+	parsed, err := Parse(`{"created_at": "02/01/2026"}`)
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+
+	var u decodeUser
+	opts := DecodeOptions{TimeLayout: "02/01/2006"}
+	if err := DecodeValueWithOptions(parsed, &u, opts); err != nil {
+		t.Fatalf("DecodeValueWithOptions error: %v", err)
+	}
+	want := time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+	if !u.CreatedAt.Equal(want) {
+		t.Errorf("got created_at=%v, want %v", u.CreatedAt, want)
+	}
+}
+
+type validatedAccount struct {
+	Balance float64 `json:"balance"`
+}
+
+func (a *validatedAccount) Validate() error {
+	if a.Balance < 0 {
+		return errAccountOverdrawn
+	}
+	return nil
+}
+
+var errAccountOverdrawn = errDecodeTest("balance must not be negative")
+
+type errDecodeTest string
+
+func (e errDecodeTest) Error() string { return string(e) }
+
+func TestDecodeCallsValidator(t *testing.T) {
+	var a validatedAccount
+	err := Decode(`{"balance": -5}`, &a)
+	if err == nil {
+		t.Fatalf("expected a validation error")
+	}
+	if !strings.Contains(err.Error(), "balance must not be negative") {
+		t.Errorf("Error() = %q, want it to mention the Validate failure", err.Error())
+	}
+}
+
+func TestDecodeIntKeyedMap(t *testing.T) {
+	var m map[int]string
+	if err := Decode(`{"1": "a", "2": "b"}`, &m); err != nil {
+		t.Fatalf("Decode error: %v", err)
+	}
+	if m[1] != "a" || m[2] != "b" {
+		t.Errorf("got %v, want map[1:a 2:b]", m)
+	}
+}
+
+func TestDecodeMapKeyInvalid(t *testing.T) {
+	var m map[int]string
+	err := Decode(`{"nope": "a"}`, &m)
+	if err == nil {
+		t.Fatalf("expected an error decoding a non-numeric key into map[int]string")
+	}
+}
+
+func TestDecodeIntOverflow(t *testing.T) {
+	var s struct {
+		N int8 `json:"n"`
+	}
+	err := Decode(`{"n": 1000}`, &s)
+	if err == nil {
+		t.Fatalf("expected an overflow error assigning 1000 to int8")
+	}
+}
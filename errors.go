@@ -0,0 +1,192 @@
+package smolparser
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParseError is a syntax error encountered while lexing or parsing, with
+// enough context to point a user at the offending source.
+type ParseError struct {
+	Msg     string
+	Line    int
+	Col     int
+	Snippet string
+	Path    []interface{} // object keys / array indices leading to the error, outermost first
+}
+
+// Error renders the error GCC-style: "line:col: message", followed by a
+// caret snippet of the offending source when one is available.
+func (e *ParseError) Error() string {
+	s := fmt.Sprintf("%d:%d: %s", e.Line, e.Col, e.Msg)
+	if e.Snippet != "" {
+		s += "\n" + e.Snippet
+	}
+	return s
+}
+
+// ErrorList collects every ParseError found during a parse with
+// Options.CollectErrors enabled.
+type ErrorList []*ParseError
+
+func (l ErrorList) Error() string {
+	msgs := make([]string, len(l))
+	for i, e := range l {
+		msgs[i] = e.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// asParseError coerces any error into a *ParseError, wrapping it without
+// position information if it isn't one already.
+func asParseError(err error) *ParseError {
+	if pe, ok := err.(*ParseError); ok {
+		return pe
+	}
+	return &ParseError{Msg: err.Error()}
+}
+
+// errorf builds a *ParseError positioned at the lexer's current location.
+func (l *Lexer) errorf(format string, args ...interface{}) error {
+	return &ParseError{
+		Msg:     fmt.Sprintf(format, args...),
+		Line:    l.line,
+		Col:     l.col,
+		Snippet: l.snippet(l.line, l.col),
+	}
+}
+
+// snippet returns the source line at (line, col) plus a caret pointing at
+// col, or "" if the Lexer wasn't constructed from a known source string
+// (e.g. it is reading from an arbitrary io.Reader).
+func (l *Lexer) snippet(line, col int) string {
+	if l.source == "" {
+		return ""
+	}
+	lines := strings.Split(l.source, "\n")
+	idx := line - 1
+	if idx < 0 || idx >= len(lines) {
+		return ""
+	}
+	caretPos := col - 1
+	if caretPos < 0 {
+		caretPos = 0
+	}
+	return lines[idx] + "\n" + strings.Repeat(" ", caretPos) + "^"
+}
+
+// errorf builds a *ParseError positioned at the parser's current token,
+// tagged with the object keys / array indices enclosing it.
+func (p *Parser) errorf(format string, args ...interface{}) error {
+	pe := &ParseError{
+		Msg:  fmt.Sprintf(format, args...),
+		Line: p.curToken.Line,
+		Col:  p.curToken.Col,
+		Path: append([]interface{}(nil), p.path...),
+	}
+	// Only a string-backed *Lexer can render a source snippet; the
+	// channel-backed pipelineLexer has no such method.
+	if l, ok := p.lexer.(*Lexer); ok {
+		pe.Snippet = l.snippet(p.curToken.Line, p.curToken.Col)
+	}
+	return pe
+}
+
+// recoverOrFail records err when Options.CollectErrors is set and attempts
+// to resynchronize the token stream; otherwise it returns err unchanged so
+// the caller aborts immediately.
+func (p *Parser) recoverOrFail(err error) error {
+	if !p.opts.CollectErrors {
+		return err
+	}
+	p.errs = append(p.errs, asParseError(err))
+	return p.recover()
+}
+
+// recover discards tokens until the next top-level ',', '}' or ']' (not
+// consuming it), so the enclosing object/array parser can decide whether
+// to continue with the next entry or close the container.
+func (p *Parser) recover() error {
+	// The error that triggered recovery may itself have come from a
+	// failed advance() (e.g. a lexer error reading the value after a
+	// colon), in which case p.curToken is stale: it still holds
+	// whatever token preceded the failed advance, not the bad input
+	// that caused it. Resync against the lexer directly before trusting
+	// it, so recovery starts from the actual current position.
+	if p.curTokenStale {
+		p.resync()
+	}
+	depth := 0
+	for {
+		switch p.curToken.Type {
+		case TokenEOF:
+			return p.errorf("unexpected end of input during error recovery")
+		case TokenLeftBrace, TokenLeftBracket:
+			depth++
+		case TokenRightBrace, TokenRightBracket:
+			if depth == 0 {
+				return nil
+			}
+			depth--
+		case TokenComma:
+			if depth == 0 {
+				return nil
+			}
+		}
+		p.resync()
+	}
+}
+
+// resync advances p.curToken to the next token that lexes cleanly,
+// silently discarding any further lexer errors along the way (rather than
+// returning the first one, like advance() does). Without this, a run of
+// several consecutive illegal characters (e.g. "@@@") would make recover
+// bail out on the second one instead of skipping the whole run.
+func (p *Parser) resync() {
+	for {
+		tok, err := p.lexer.NextToken()
+		if err == nil {
+			p.curToken = tok
+			p.curTokenStale = false
+			return
+		}
+	}
+}
+
+// afterObjectRecovery decides what to do once recover() has resynced the
+// token stream inside an object: close it, continue with the next key, or
+// give up if neither a comma nor a closing brace was found.
+func (p *Parser) afterObjectRecovery() (done bool, err error) {
+	switch p.curToken.Type {
+	case TokenRightBrace:
+		if err := p.advance(); err != nil {
+			return false, err
+		}
+		return true, nil
+	case TokenComma:
+		if err := p.advance(); err != nil {
+			return false, err
+		}
+		return false, nil
+	default:
+		return false, p.errorf("unable to recover after malformed object entry")
+	}
+}
+
+// afterArrayRecovery is afterObjectRecovery's array counterpart.
+func (p *Parser) afterArrayRecovery() (done bool, err error) {
+	switch p.curToken.Type {
+	case TokenRightBracket:
+		if err := p.advance(); err != nil {
+			return false, err
+		}
+		return true, nil
+	case TokenComma:
+		if err := p.advance(); err != nil {
+			return false, err
+		}
+		return false, nil
+	default:
+		return false, p.errorf("unable to recover after malformed array element")
+	}
+}
@@ -0,0 +1,92 @@
+package smolparser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseErrorLineCol(t *testing.T) {
+	input := "{\n  \"a\": 1,\n  \"b\": tru\n}"
+	_, err := Parse(input)
+	if err == nil {
+		t.Fatalf("Parse(%q) should have returned an error", input)
+	}
+	pe, ok := err.(*ParseError)
+	if !ok {
+		t.Fatalf("err is %T, want *ParseError", err)
+	}
+	if pe.Line != 3 {
+		t.Errorf("Line = %d, want 3", pe.Line)
+	}
+	if !strings.Contains(pe.Error(), "3:") {
+		t.Errorf("Error() = %q, want it to mention line 3", pe.Error())
+	}
+	if pe.Snippet == "" {
+		t.Errorf("Snippet should not be empty for a string-backed parse")
+	}
+}
+
+func TestParseErrorPath(t *testing.T) {
+	input := `{"a": {"b" 5}}`
+	_, err := Parse(input)
+	if err == nil {
+		t.Fatalf("Parse(%q) should have returned an error", input)
+	}
+	pe, ok := err.(*ParseError)
+	if !ok {
+		t.Fatalf("err is %T, want *ParseError", err)
+	}
+	want := []interface{}{"a", "b"}
+	if len(pe.Path) != len(want) || pe.Path[0] != want[0] || pe.Path[1] != want[1] {
+		t.Errorf("Path = %v, want %v", pe.Path, want)
+	}
+}
+
+func TestCollectErrorsRecoversWithinObject(t *testing.T) {
+	input := `{"a": 1, "b": @@@, "c": 3}`
+	opts := Options{CollectErrors: true}
+
+	val, err := ParseWithOptions(input, opts)
+	if err == nil {
+		t.Fatalf("expected a non-nil ErrorList error")
+	}
+	list, ok := err.(ErrorList)
+	if !ok {
+		t.Fatalf("err is %T, want ErrorList", err)
+	}
+	if len(list) != 1 {
+		t.Fatalf("got %d errors, want 1: %v", len(list), list)
+	}
+
+	obj, ok := val.(map[string]interface{})
+	if !ok {
+		t.Fatalf("val is %T, want map[string]interface{}", val)
+	}
+	if obj["a"] != 1.0 || obj["c"] != 3.0 {
+		t.Errorf("partial object = %v, want a=1 and c=3 to survive", obj)
+	}
+	if _, ok := obj["b"]; ok {
+		t.Errorf("malformed entry %q should have been dropped", "b")
+	}
+}
+
+func TestCollectErrorsRecoversWithinArray(t *testing.T) {
+	input := `[1, @@@, 3]`
+	opts := Options{CollectErrors: true}
+
+	val, err := ParseWithOptions(input, opts)
+	if err == nil {
+		t.Fatalf("expected a non-nil ErrorList error")
+	}
+	if _, ok := err.(ErrorList); !ok {
+		t.Fatalf("err is %T, want ErrorList", err)
+	}
+
+	arr, ok := val.([]interface{})
+	if !ok {
+		t.Fatalf("val is %T, want []interface{}", val)
+	}
+	if len(arr) != 2 || arr[0] != 1.0 || arr[1] != 3.0 {
+		t.Errorf("partial array = %v, want [1 3]", arr)
+	}
+}
@@ -0,0 +1,207 @@
+package jsonpath
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// filterExpr is a compiled `[?( ... )]` predicate. It is evaluated by
+// walking the candidate subtree with the same operator machinery used by
+// Path, so a filter's @-paths can themselves contain path segments.
+type filterExpr struct {
+	ors []andClause
+}
+
+type andClause struct {
+	cmps []comparison
+}
+
+// comparison is either a bare truthy check of left (op == "") or a binary
+// comparison between left and right.
+type comparison struct {
+	left, right term
+	op          string
+}
+
+type term struct {
+	path *Path // set when the term is an @ expression
+	lit  interface{}
+}
+
+func (f *filterExpr) eval(cur interface{}) bool {
+	for _, clause := range f.ors {
+		if clause.eval(cur) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c andClause) eval(cur interface{}) bool {
+	for _, cmp := range c.cmps {
+		if !cmp.eval(cur) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c comparison) eval(cur interface{}) bool {
+	left := c.left.resolve(cur)
+	if c.op == "" {
+		return truthy(left)
+	}
+	right := c.right.resolve(cur)
+	return compare(left, right, c.op)
+}
+
+func (t term) resolve(cur interface{}) interface{} {
+	if t.path == nil {
+		return t.lit
+	}
+	matches, _ := t.path.Eval(cur)
+	if len(matches) == 0 {
+		return nil
+	}
+	return matches[0]
+}
+
+func truthy(v interface{}) bool {
+	switch t := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return t
+	default:
+		return true
+	}
+}
+
+func compare(left, right interface{}, op string) bool {
+	if lf, rf, ok := asFloats(left, right); ok {
+		switch op {
+		case "==":
+			return lf == rf
+		case "!=":
+			return lf != rf
+		case "<":
+			return lf < rf
+		case "<=":
+			return lf <= rf
+		case ">":
+			return lf > rf
+		case ">=":
+			return lf >= rf
+		}
+	}
+
+	ls, lok := left.(string)
+	rs, rok := right.(string)
+	if lok && rok {
+		switch op {
+		case "==":
+			return ls == rs
+		case "!=":
+			return ls != rs
+		case "<":
+			return ls < rs
+		case "<=":
+			return ls <= rs
+		case ">":
+			return ls > rs
+		case ">=":
+			return ls >= rs
+		}
+	}
+
+	switch op {
+	case "==":
+		return left == right
+	case "!=":
+		return left != right
+	}
+	return false
+}
+
+func asFloats(left, right interface{}) (float64, float64, bool) {
+	lf, lok := left.(float64)
+	rf, rok := right.(float64)
+	return lf, rf, lok && rok
+}
+
+// compileFilter parses the inside of a `?( ... )` filter expression, e.g.
+// `@.price < 10 && @.tag == "x"`.
+func compileFilter(src string) (*filterExpr, error) {
+	src = strings.TrimSpace(src)
+	var ors []andClause
+	for _, orPart := range strings.Split(src, "||") {
+		var cmps []comparison
+		for _, andPart := range strings.Split(orPart, "&&") {
+			cmp, err := compileComparison(strings.TrimSpace(andPart))
+			if err != nil {
+				return nil, err
+			}
+			cmps = append(cmps, cmp)
+		}
+		ors = append(ors, andClause{cmps: cmps})
+	}
+	return &filterExpr{ors: ors}, nil
+}
+
+var comparisonOps = []string{"<=", ">=", "==", "!=", "<", ">"}
+
+func compileComparison(src string) (comparison, error) {
+	for _, op := range comparisonOps {
+		if idx := strings.Index(src, op); idx >= 0 {
+			left, err := compileTerm(strings.TrimSpace(src[:idx]))
+			if err != nil {
+				return comparison{}, err
+			}
+			right, err := compileTerm(strings.TrimSpace(src[idx+len(op):]))
+			if err != nil {
+				return comparison{}, err
+			}
+			return comparison{left: left, right: right, op: op}, nil
+		}
+	}
+	left, err := compileTerm(src)
+	if err != nil {
+		return comparison{}, err
+	}
+	return comparison{left: left}, nil
+}
+
+func compileTerm(src string) (term, error) {
+	src = strings.TrimSpace(src)
+	if src == "" {
+		return term{}, fmt.Errorf("jsonpath: empty term in filter")
+	}
+
+	if strings.HasPrefix(src, "@") {
+		ops, err := parsePath("$" + src[1:])
+		if err != nil {
+			return term{}, err
+		}
+		return term{path: &Path{ops: ops}}, nil
+	}
+
+	switch src {
+	case "true":
+		return term{lit: true}, nil
+	case "false":
+		return term{lit: false}, nil
+	case "null":
+		return term{lit: nil}, nil
+	}
+
+	if len(src) >= 2 && (src[0] == '\'' || src[0] == '"') && src[len(src)-1] == src[0] {
+		return term{lit: src[1 : len(src)-1]}, nil
+	}
+
+	if f, err := strconv.ParseFloat(src, 64); err == nil {
+		return term{lit: f}, nil
+	}
+
+	return term{}, fmt.Errorf("jsonpath: unrecognized filter term %q", src)
+}
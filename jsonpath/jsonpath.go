@@ -0,0 +1,489 @@
+// Package jsonpath runs JSONPath-style queries against the interface{}
+// trees produced by smolparser.Parse.
+package jsonpath
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	smolparser "smol-go/smol-parser"
+)
+
+// Path is a compiled JSONPath expression.
+type Path struct {
+	ops []operator
+}
+
+// Result is one value produced by EvalStream, or the terminal error.
+type Result struct {
+	Value interface{}
+	Err   error
+}
+
+type opKind int
+
+const (
+	opName opKind = iota
+	opIndex
+	opSlice
+	opUnion
+	opWildcard
+	opDescent
+	opFilter
+)
+
+// operator is one step of the compiled path, matched against the current
+// set of located values (the "location stack").
+type operator struct {
+	kind opKind
+
+	name  string   // opName
+	index int      // opIndex
+	names []string // opUnion of ['a','b']
+	idxes []int    // opUnion of [0,2]
+
+	start, end, step int
+	hasStart, hasEnd bool // opSlice
+
+	filter *filterExpr // opFilter
+}
+
+// Compile parses a JSONPath expression such as
+// "$.store.book[*].author" or "$..book[?(@.price < 10)]".
+func Compile(expr string) (*Path, error) {
+	ops, err := parsePath(expr)
+	if err != nil {
+		return nil, err
+	}
+	return &Path{ops: ops}, nil
+}
+
+// Eval runs the path against an already-parsed value tree and returns every
+// matching node.
+func (p *Path) Eval(root interface{}) ([]interface{}, error) {
+	cur := []interface{}{root}
+	for _, op := range p.ops {
+		cur = apply(op, cur)
+	}
+	return cur, nil
+}
+
+// EvalStream evaluates the path against r using smolparser's
+// token-driven StreamParser rather than reading r fully into memory and
+// parsing it as one string. When the path's first segment selects
+// elements of a top-level array ("$[*]", "$[0]", "$[1:3]", ...), matches
+// are produced as each array element is decoded, so a caller never needs
+// to hold more than one element in memory at a time; for any other path
+// shape, the document still has to be fully decoded before it can be
+// walked, but that decoding itself runs off the streaming Lexer rather
+// than a preloaded string.
+func (p *Path) EvalStream(r io.Reader) (<-chan Result, error) {
+	sp, err := smolparser.NewStreamParser(r)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan Result)
+	go func() {
+		defer close(out)
+
+		if len(p.ops) > 0 && isArrayElementOp(p.ops[0]) {
+			p.evalStreamedArray(sp, out)
+			return
+		}
+
+		root, err := sp.ReadValue()
+		if err != nil {
+			out <- Result{Err: err}
+			return
+		}
+		matches, err := p.Eval(root)
+		if err != nil {
+			out <- Result{Err: err}
+			return
+		}
+		for _, m := range matches {
+			out <- Result{Value: m}
+		}
+	}()
+	return out, nil
+}
+
+// isArrayElementOp reports whether op selects elements of an array by
+// position, meaning it can be evaluated one top-level array element at a
+// time via StreamParser.ForEachInArray instead of against a fully
+// materialized array.
+func isArrayElementOp(op operator) bool {
+	switch op.kind {
+	case opIndex, opSlice, opWildcard:
+		return true
+	case opUnion:
+		// A union of quoted names (['a','b']) selects object keys, not
+		// array positions.
+		return len(op.names) == 0
+	default:
+		return false
+	}
+}
+
+// evalStreamedArray evaluates p against r's top-level array one element
+// at a time: p.ops[0] decides which elements are selected by position,
+// and the remaining ops are evaluated against each selected element in
+// turn via the ordinary (in-memory) Eval.
+func (p *Path) evalStreamedArray(sp *smolparser.StreamParser, out chan<- Result) {
+	first := p.ops[0]
+	rest := &Path{ops: p.ops[1:]}
+
+	idx := 0
+	err := sp.ForEachInArray(func(elem interface{}) error {
+		selected := matchesArrayOp(first, idx)
+		idx++
+		if !selected {
+			return nil
+		}
+		matches, err := rest.Eval(elem)
+		if err != nil {
+			return err
+		}
+		for _, m := range matches {
+			out <- Result{Value: m}
+		}
+		return nil
+	})
+	if err != nil {
+		out <- Result{Err: err}
+	}
+}
+
+// matchesArrayOp reports whether the array element at idx is selected by
+// op. Negative indices/slice bounds are resolved relative to the array's
+// length in the ordinary (non-streaming) apply(), which isn't known yet
+// while elements are still arriving one at a time, so they never match
+// here.
+func matchesArrayOp(op operator, idx int) bool {
+	switch op.kind {
+	case opWildcard:
+		return true
+	case opIndex:
+		return op.index >= 0 && idx == op.index
+	case opUnion:
+		for _, i := range op.idxes {
+			if i == idx {
+				return true
+			}
+		}
+		return false
+	case opSlice:
+		return matchesStreamedSlice(op, idx)
+	default:
+		return false
+	}
+}
+
+func matchesStreamedSlice(op operator, idx int) bool {
+	step := op.step
+	if step == 0 {
+		step = 1
+	}
+	if step <= 0 || (op.hasStart && op.start < 0) || (op.hasEnd && op.end < 0) {
+		return false
+	}
+	start := 0
+	if op.hasStart {
+		start = op.start
+	}
+	if idx < start || (op.hasEnd && idx >= op.end) {
+		return false
+	}
+	return (idx-start)%step == 0
+}
+
+// apply runs a single operator against the current location stack,
+// producing the next location stack.
+func apply(op operator, cur []interface{}) []interface{} {
+	var next []interface{}
+	for _, v := range cur {
+		switch op.kind {
+		case opName:
+			if m, ok := v.(map[string]interface{}); ok {
+				if val, ok := m[op.name]; ok {
+					next = append(next, val)
+				}
+			}
+		case opUnion:
+			if m, ok := v.(map[string]interface{}); ok {
+				for _, n := range op.names {
+					if val, ok := m[n]; ok {
+						next = append(next, val)
+					}
+				}
+			}
+			if arr, ok := v.([]interface{}); ok {
+				for _, i := range op.idxes {
+					if idx := normalizeIndex(i, len(arr)); idx >= 0 && idx < len(arr) {
+						next = append(next, arr[idx])
+					}
+				}
+			}
+		case opIndex:
+			if arr, ok := v.([]interface{}); ok {
+				if idx := normalizeIndex(op.index, len(arr)); idx >= 0 && idx < len(arr) {
+					next = append(next, arr[idx])
+				}
+			}
+		case opSlice:
+			if arr, ok := v.([]interface{}); ok {
+				next = append(next, sliceArray(arr, op)...)
+			}
+		case opWildcard:
+			next = append(next, children(v)...)
+		case opDescent:
+			next = append(next, descend(v)...)
+		case opFilter:
+			for _, c := range children(v) {
+				if op.filter.eval(c) {
+					next = append(next, c)
+				}
+			}
+		}
+	}
+	return next
+}
+
+func children(v interface{}) []interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		out := make([]interface{}, 0, len(t))
+		for _, val := range t {
+			out = append(out, val)
+		}
+		return out
+	case []interface{}:
+		return append([]interface{}{}, t...)
+	default:
+		return nil
+	}
+}
+
+// descend returns v plus every descendant of v, depth-first, implementing
+// the recursive-descent (..) operator.
+func descend(v interface{}) []interface{} {
+	out := []interface{}{v}
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for _, val := range t {
+			out = append(out, descend(val)...)
+		}
+	case []interface{}:
+		for _, val := range t {
+			out = append(out, descend(val)...)
+		}
+	}
+	return out
+}
+
+func normalizeIndex(i, n int) int {
+	if i < 0 {
+		return n + i
+	}
+	return i
+}
+
+func sliceArray(arr []interface{}, op operator) []interface{} {
+	n := len(arr)
+	step := op.step
+	if step == 0 {
+		step = 1
+	}
+	start, end := 0, n
+	if op.hasStart {
+		start = normalizeIndex(op.start, n)
+	}
+	if op.hasEnd {
+		end = normalizeIndex(op.end, n)
+	}
+	if start < 0 {
+		start = 0
+	}
+	if end > n {
+		end = n
+	}
+
+	var out []interface{}
+	if step > 0 {
+		for i := start; i < end; i += step {
+			out = append(out, arr[i])
+		}
+	} else {
+		for i := start; i > end; i += step {
+			if i >= 0 && i < n {
+				out = append(out, arr[i])
+			}
+		}
+	}
+	return out
+}
+
+// ---- compiler: expr -> []operator ----
+
+func parsePath(expr string) ([]operator, error) {
+	expr = strings.TrimSpace(expr)
+	if !strings.HasPrefix(expr, "$") {
+		return nil, fmt.Errorf("jsonpath: expression must start with $, got %q", expr)
+	}
+	rest := expr[1:]
+
+	var ops []operator
+	for len(rest) > 0 {
+		switch {
+		case strings.HasPrefix(rest, ".."):
+			ops = append(ops, operator{kind: opDescent})
+			rest = rest[2:]
+			seg, tail, err := readBareSegment(rest)
+			if err != nil {
+				return nil, err
+			}
+			if seg != "" {
+				ops = append(ops, operator{kind: opName, name: seg})
+			}
+			rest = tail
+
+		case strings.HasPrefix(rest, "."):
+			rest = rest[1:]
+			seg, tail, err := readBareSegment(rest)
+			if err != nil {
+				return nil, err
+			}
+			if seg == "*" {
+				ops = append(ops, operator{kind: opWildcard})
+			} else if seg != "" {
+				ops = append(ops, operator{kind: opName, name: seg})
+			}
+			rest = tail
+
+		case strings.HasPrefix(rest, "["):
+			end := strings.Index(rest, "]")
+			if end < 0 {
+				return nil, fmt.Errorf("jsonpath: unterminated [ in %q", expr)
+			}
+			inner := rest[1:end]
+			op, err := parseBracket(inner)
+			if err != nil {
+				return nil, err
+			}
+			ops = append(ops, op)
+			rest = rest[end+1:]
+
+		default:
+			return nil, fmt.Errorf("jsonpath: unexpected input %q", rest)
+		}
+	}
+	return ops, nil
+}
+
+// readBareSegment reads an identifier up to the next '.' or '['.
+func readBareSegment(s string) (seg, rest string, err error) {
+	i := 0
+	for i < len(s) && s[i] != '.' && s[i] != '[' {
+		i++
+	}
+	return s[:i], s[i:], nil
+}
+
+func parseBracket(inner string) (operator, error) {
+	inner = strings.TrimSpace(inner)
+
+	switch {
+	case inner == "*":
+		return operator{kind: opWildcard}, nil
+
+	case strings.HasPrefix(inner, "?("):
+		if !strings.HasSuffix(inner, ")") {
+			return operator{}, fmt.Errorf("jsonpath: malformed filter %q", inner)
+		}
+		f, err := compileFilter(inner[2 : len(inner)-1])
+		if err != nil {
+			return operator{}, err
+		}
+		return operator{kind: opFilter, filter: f}, nil
+
+	case strings.HasPrefix(inner, "'") || strings.HasPrefix(inner, "\""):
+		// ['name'] or union ['a','b']
+		names, err := splitQuoted(inner)
+		if err != nil {
+			return operator{}, err
+		}
+		if len(names) == 1 {
+			return operator{kind: opName, name: names[0]}, nil
+		}
+		return operator{kind: opUnion, names: names}, nil
+
+	case strings.Contains(inner, ":"):
+		return parseSlice(inner)
+
+	case strings.Contains(inner, ","):
+		parts := strings.Split(inner, ",")
+		idxes := make([]int, 0, len(parts))
+		for _, p := range parts {
+			n, err := strconv.Atoi(strings.TrimSpace(p))
+			if err != nil {
+				return operator{}, fmt.Errorf("jsonpath: bad index %q", p)
+			}
+			idxes = append(idxes, n)
+		}
+		return operator{kind: opUnion, idxes: idxes}, nil
+
+	default:
+		n, err := strconv.Atoi(inner)
+		if err != nil {
+			return operator{}, fmt.Errorf("jsonpath: bad index %q", inner)
+		}
+		return operator{kind: opIndex, index: n}, nil
+	}
+}
+
+func splitQuoted(inner string) ([]string, error) {
+	var names []string
+	for _, part := range strings.Split(inner, ",") {
+		part = strings.TrimSpace(part)
+		if len(part) < 2 {
+			return nil, fmt.Errorf("jsonpath: bad quoted name %q", part)
+		}
+		names = append(names, part[1:len(part)-1])
+	}
+	return names, nil
+}
+
+func parseSlice(inner string) (operator, error) {
+	parts := strings.Split(inner, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return operator{}, fmt.Errorf("jsonpath: bad slice %q", inner)
+	}
+	op := operator{kind: opSlice, step: 1}
+	if s := strings.TrimSpace(parts[0]); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return operator{}, err
+		}
+		op.start, op.hasStart = n, true
+	}
+	if s := strings.TrimSpace(parts[1]); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return operator{}, err
+		}
+		op.end, op.hasEnd = n, true
+	}
+	if len(parts) == 3 {
+		if s := strings.TrimSpace(parts[2]); s != "" {
+			n, err := strconv.Atoi(s)
+			if err != nil {
+				return operator{}, err
+			}
+			op.step = n
+		}
+	}
+	return op, nil
+}
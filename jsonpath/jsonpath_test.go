@@ -0,0 +1,186 @@
+package jsonpath
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	smolparser "smol-go/smol-parser"
+)
+
+func mustParse(t *testing.T, input string) interface{} {
+	t.Helper()
+	v, err := smolparser.Parse(input)
+	if err != nil {
+		t.Fatalf("Parse(%q) error: %v", input, err)
+	}
+	return v
+}
+
+func TestEvalBasics(t *testing.T) {
+	root := mustParse(t, `{"store":{"name":"acme","books":[{"title":"a","price":5},{"title":"b","price":15}]}}`)
+
+	tests := []struct {
+		expr string
+		want []interface{}
+	}{
+		{`$.store.name`, []interface{}{"acme"}},
+		{`$.store.books[0].title`, []interface{}{"a"}},
+		{`$.store.books[*].title`, []interface{}{"a", "b"}},
+		{`$.store.books[0:1].title`, []interface{}{"a"}},
+		{`$..title`, []interface{}{"a", "b"}},
+	}
+
+	for _, tt := range tests {
+		path, err := Compile(tt.expr)
+		if err != nil {
+			t.Fatalf("Compile(%q) error: %v", tt.expr, err)
+		}
+		got, err := path.Eval(root)
+		if err != nil {
+			t.Fatalf("Eval(%q) error: %v", tt.expr, err)
+		}
+		sortStrings(got)
+		sortStrings(tt.want)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Eval(%q) = %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestEvalFilter(t *testing.T) {
+	root := mustParse(t, `{"books":[{"title":"cheap","price":5},{"title":"pricey","price":25}]}`)
+
+	path, err := Compile(`$.books[?(@.price < 10)].title`)
+	if err != nil {
+		t.Fatalf("Compile error: %v", err)
+	}
+	got, err := path.Eval(root)
+	if err != nil {
+		t.Fatalf("Eval error: %v", err)
+	}
+	want := []interface{}{"cheap"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Eval = %v, want %v", got, want)
+	}
+}
+
+func TestEvalFilterAnd(t *testing.T) {
+	root := mustParse(t, `{"books":[{"title":"a","price":5,"tag":"x"},{"title":"b","price":5,"tag":"y"}]}`)
+
+	path, err := Compile(`$.books[?(@.price < 10 && @.tag == "x")].title`)
+	if err != nil {
+		t.Fatalf("Compile error: %v", err)
+	}
+	got, err := path.Eval(root)
+	if err != nil {
+		t.Fatalf("Eval error: %v", err)
+	}
+	want := []interface{}{"a"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Eval = %v, want %v", got, want)
+	}
+}
+
+func TestEvalStreamArray(t *testing.T) {
+	input := `[{"title":"a","price":5},{"title":"b","price":15},{"title":"c","price":25}]`
+
+	path, err := Compile(`$[*].title`)
+	if err != nil {
+		t.Fatalf("Compile error: %v", err)
+	}
+	ch, err := path.EvalStream(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("EvalStream error: %v", err)
+	}
+
+	var got []interface{}
+	for r := range ch {
+		if r.Err != nil {
+			t.Fatalf("EvalStream result error: %v", r.Err)
+		}
+		got = append(got, r.Value)
+	}
+	want := []interface{}{"a", "b", "c"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("EvalStream(%q) = %v, want %v", `$[*].title`, got, want)
+	}
+}
+
+func TestEvalStreamArrayIndex(t *testing.T) {
+	input := `[10, 20, 30]`
+
+	path, err := Compile(`$[1]`)
+	if err != nil {
+		t.Fatalf("Compile error: %v", err)
+	}
+	ch, err := path.EvalStream(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("EvalStream error: %v", err)
+	}
+
+	var got []interface{}
+	for r := range ch {
+		if r.Err != nil {
+			t.Fatalf("EvalStream result error: %v", r.Err)
+		}
+		got = append(got, r.Value)
+	}
+	want := []interface{}{20.0}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("EvalStream(%q) = %v, want %v", `$[1]`, got, want)
+	}
+}
+
+func TestEvalStreamNonArrayPath(t *testing.T) {
+	input := `{"store":{"name":"acme","books":[{"title":"a"},{"title":"b"}]}}`
+
+	path, err := Compile(`$..title`)
+	if err != nil {
+		t.Fatalf("Compile error: %v", err)
+	}
+	ch, err := path.EvalStream(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("EvalStream error: %v", err)
+	}
+
+	var got []interface{}
+	for r := range ch {
+		if r.Err != nil {
+			t.Fatalf("EvalStream result error: %v", r.Err)
+		}
+		got = append(got, r.Value)
+	}
+	sortStrings(got)
+	want := []interface{}{"a", "b"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("EvalStream(%q) = %v, want %v", `$..title`, got, want)
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	tests := []string{
+		`store.name`,
+		`$[`,
+		`$.books[?(@.price <)]`,
+	}
+	for _, expr := range tests {
+		if _, err := Compile(expr); err == nil {
+			t.Errorf("Compile(%q) should have returned an error", expr)
+		}
+	}
+}
+
+func sortStrings(vs []interface{}) {
+	sort.Slice(vs, func(i, j int) bool {
+		return asString(vs[i]) < asString(vs[j])
+	})
+}
+
+func asString(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	return ""
+}
@@ -1,8 +1,14 @@
-package main
+// Package smolparser implements a small, dependency-free JSON parser that
+// exposes both the parsed value and the underlying lexer/parser types so
+// other packages in this module (e.g. jsonpath) can build on top of them.
+package smolparser
 
 import (
-	"fmt"
+	"bufio"
+	"io"
+	"math"
 	"strconv"
+	"strings"
 	"unicode"
 )
 
@@ -22,6 +28,7 @@ const (
 	TokenTrue
 	TokenFalse
 	TokenNull
+	TokenIdent // bare identifier, only produced when Options.AllowUnquotedKeys is set
 )
 
 // Token represents a lexical token
@@ -29,63 +36,132 @@ type Token struct {
 	Type  TokenType
 	Value string
 	Pos   int
+	Line  int
+	Col   int
 }
 
-// Lexer performs lexical analysis
+// Lexer performs lexical analysis. It reads from a bufio.Reader rather
+// than a whole in-memory string so large documents can be tokenized
+// without first being read fully into memory.
 type Lexer struct {
-	input string
-	pos   int
-	ch    byte
+	r    *bufio.Reader
+	pos  int
+	ch   byte
+	line int
+	col  int
+	opts Options
+
+	// source holds the original input when the Lexer was built from a
+	// string (NewLexer/newLexerFromString), so errorf can render a caret
+	// snippet. It is empty for Lexers built from an arbitrary io.Reader.
+	source string
 }
 
+// NewLexer returns a strict, RFC 8259 Lexer over an in-memory string.
 func NewLexer(input string) *Lexer {
-	l := &Lexer{input: input}
+	return newLexerFromString(input, Options{})
+}
+
+// newLexerFromString returns a Lexer over an in-memory string, retaining
+// the source so errors can include a snippet.
+func newLexerFromString(input string, opts Options) *Lexer {
+	l := newLexerWithOptions(strings.NewReader(input), opts)
+	l.source = input
+	return l
+}
+
+// newLexerFromReader returns a strict Lexer reading from an arbitrary
+// io.Reader.
+func newLexerFromReader(r io.Reader) *Lexer {
+	return newLexerWithOptions(r, Options{})
+}
+
+// newLexerWithOptions returns a Lexer reading from r with the relaxed
+// dialect extensions in opts enabled.
+func newLexerWithOptions(r io.Reader, opts Options) *Lexer {
+	l := &Lexer{r: bufio.NewReader(r), opts: opts, line: 1}
 	l.readChar()
 	return l
 }
 
 func (l *Lexer) readChar() {
-	if l.pos >= len(l.input) {
+	prevCh := l.ch
+	b, err := l.r.ReadByte()
+	if err != nil {
 		l.ch = 0
 	} else {
-		l.ch = l.input[l.pos]
+		l.ch = b
 	}
 	l.pos++
+	if prevCh == '\n' {
+		l.line++
+		l.col = 1
+	} else {
+		l.col++
+	}
 }
 
 func (l *Lexer) peekChar() byte {
-	if l.pos >= len(l.input) {
+	b, err := l.r.Peek(1)
+	if err != nil || len(b) == 0 {
 		return 0
 	}
-	return l.input[l.pos]
+	return b[0]
 }
 
 func (l *Lexer) skipWhitespace() {
-	for l.ch == ' ' || l.ch == '\t' || l.ch == '\n' || l.ch == '\r' {
-		l.readChar()
+	for {
+		for l.ch == ' ' || l.ch == '\t' || l.ch == '\n' || l.ch == '\r' {
+			l.readChar()
+		}
+
+		if !l.opts.AllowComments || l.ch != '/' {
+			return
+		}
+
+		switch l.peekChar() {
+		case '/':
+			for l.ch != '\n' && l.ch != 0 {
+				l.readChar()
+			}
+		case '*':
+			l.readChar() // '/'
+			l.readChar() // '*'
+			for !(l.ch == '*' && l.peekChar() == '/') && l.ch != 0 {
+				l.readChar()
+			}
+			if l.ch != 0 {
+				l.readChar() // '*'
+				l.readChar() // '/'
+			}
+		default:
+			return
+		}
 	}
 }
 
-func (l *Lexer) readString() (string, error) {
-	var result []rune
-	l.readChar() // skip opening "
+// readString reads a quoted string, where quote is the opening quote
+// character ('"', or '\'' when Options.AllowSingleQuotes is set).
+func (l *Lexer) readString(quote byte) (string, error) {
+	var result strings.Builder
+	l.readChar() // skip opening quote
 
-	for l.ch != '"' && l.ch != 0 {
+	for l.ch != quote && l.ch != 0 {
 		if l.ch == '\\' {
 			l.readChar()
 			switch l.ch {
-			case '"', '\\', '/':
-				result = append(result, rune(l.ch))
+			case '"', '\'', '\\', '/':
+				result.WriteByte(l.ch)
 			case 'b':
-				result = append(result, '\b')
+				result.WriteByte('\b')
 			case 'f':
-				result = append(result, '\f')
+				result.WriteByte('\f')
 			case 'n':
-				result = append(result, '\n')
+				result.WriteByte('\n')
 			case 'r':
-				result = append(result, '\r')
+				result.WriteByte('\r')
 			case 't':
-				result = append(result, '\t')
+				result.WriteByte('\t')
 			case 'u':
 				// Unicode escape sequence
 				l.readChar()
@@ -96,74 +172,103 @@ func (l *Lexer) readString() (string, error) {
 				}
 				val, err := strconv.ParseInt(hex, 16, 32)
 				if err != nil {
-					return "", fmt.Errorf("invalid unicode escape: %s", hex)
+					return "", l.errorf("invalid unicode escape: %s", hex)
 				}
-				result = append(result, rune(val))
+				result.WriteRune(rune(val))
 				continue
 			default:
-				return "", fmt.Errorf("invalid escape sequence: \\%c", l.ch)
+				return "", l.errorf("invalid escape sequence: \\%c", l.ch)
 			}
 			l.readChar()
 		} else {
-			result = append(result, rune(l.ch))
+			// l.ch is a single byte of the input, which may be one
+			// byte of a multi-byte UTF-8 sequence; write it raw
+			// rather than widening it into its own rune (which would
+			// mangle any non-ASCII text).
+			result.WriteByte(l.ch)
 			l.readChar()
 		}
 	}
 
-	if l.ch != '"' {
-		return "", fmt.Errorf("unterminated string")
+	if l.ch != quote {
+		return "", l.errorf("unterminated string")
 	}
-	l.readChar() // skip closing "
-	return string(result), nil
+	l.readChar() // skip closing quote
+	return result.String(), nil
 }
 
 func (l *Lexer) readNumber() string {
-	start := l.pos - 1
+	var b strings.Builder
 
 	if l.ch == '-' {
+		b.WriteByte(l.ch)
 		l.readChar()
 	}
 
+	if l.opts.AllowHexNumbers && l.ch == '0' && (l.peekChar() == 'x' || l.peekChar() == 'X') {
+		b.WriteByte(l.ch)
+		l.readChar()
+		b.WriteByte(l.ch)
+		l.readChar()
+		for isHexDigit(l.ch) {
+			b.WriteByte(l.ch)
+			l.readChar()
+		}
+		return b.String()
+	}
+
 	if l.ch == '0' {
+		b.WriteByte(l.ch)
 		l.readChar()
 	} else {
 		for unicode.IsDigit(rune(l.ch)) {
+			b.WriteByte(l.ch)
 			l.readChar()
 		}
 	}
 
 	if l.ch == '.' {
+		b.WriteByte(l.ch)
 		l.readChar()
 		for unicode.IsDigit(rune(l.ch)) {
+			b.WriteByte(l.ch)
 			l.readChar()
 		}
 	}
 
 	if l.ch == 'e' || l.ch == 'E' {
+		b.WriteByte(l.ch)
 		l.readChar()
 		if l.ch == '+' || l.ch == '-' {
+			b.WriteByte(l.ch)
 			l.readChar()
 		}
 		for unicode.IsDigit(rune(l.ch)) {
+			b.WriteByte(l.ch)
 			l.readChar()
 		}
 	}
 
-	return l.input[start : l.pos-1]
+	return b.String()
+}
+
+func isHexDigit(ch byte) bool {
+	return unicode.IsDigit(rune(ch)) || (ch >= 'a' && ch <= 'f') || (ch >= 'A' && ch <= 'F')
 }
 
 func (l *Lexer) readIdentifier() string {
-	start := l.pos - 1
+	var b strings.Builder
 	for unicode.IsLetter(rune(l.ch)) {
+		b.WriteByte(l.ch)
 		l.readChar()
 	}
-	return l.input[start : l.pos-1]
+	return b.String()
 }
 
 func (l *Lexer) NextToken() (Token, error) {
 	l.skipWhitespace()
 
-	tok := Token{Pos: l.pos - 1}
+	tok := Token{Pos: l.pos - 1, Line: l.line, Col: l.col}
 
 	switch l.ch {
 	case 0:
@@ -187,45 +292,104 @@ func (l *Lexer) NextToken() (Token, error) {
 		tok.Type = TokenComma
 		l.readChar()
 	case '"':
-		str, err := l.readString()
+		str, err := l.readString('"')
+		if err != nil {
+			return tok, err
+		}
+		tok.Type = TokenString
+		tok.Value = str
+	case '\'':
+		if !l.opts.AllowSingleQuotes {
+			return tok, l.errorf("unexpected character: '")
+		}
+		str, err := l.readString('\'')
 		if err != nil {
 			return tok, err
 		}
 		tok.Type = TokenString
 		tok.Value = str
-	case '-', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+	case '-':
+		if l.opts.AllowNaNInf && l.peekChar() == 'I' {
+			l.readChar() // skip '-'
+			ident := l.readIdentifier()
+			if ident != "Infinity" {
+				return tok, l.errorf("unexpected identifier: -%s", ident)
+			}
+			tok.Type = TokenNumber
+			tok.Value = "-Infinity"
+			break
+		}
+		tok.Type = TokenNumber
+		tok.Value = l.readNumber()
+	case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
 		tok.Type = TokenNumber
 		tok.Value = l.readNumber()
 	default:
 		if unicode.IsLetter(rune(l.ch)) {
 			ident := l.readIdentifier()
-			switch ident {
-			case "true":
+			switch {
+			case ident == "true":
 				tok.Type = TokenTrue
-			case "false":
+			case ident == "false":
 				tok.Type = TokenFalse
-			case "null":
+			case ident == "null":
 				tok.Type = TokenNull
+			case l.opts.AllowNaNInf && (ident == "NaN" || ident == "Infinity"):
+				tok.Type = TokenNumber
+				tok.Value = ident
+			case l.opts.AllowUnquotedKeys:
+				tok.Type = TokenIdent
+				tok.Value = ident
 			default:
-				return tok, fmt.Errorf("unexpected identifier: %s", ident)
+				return tok, l.errorf("unexpected identifier: %s", ident)
 			}
 		} else {
-			return tok, fmt.Errorf("unexpected character: %c", l.ch)
+			err := l.errorf("unexpected character: %c", l.ch)
+			l.readChar() // make forward progress so a caller can resynchronize
+			return tok, err
 		}
 	}
 
 	return tok, nil
 }
 
+// tokenSource is the minimal surface Parser needs to pull tokens from: a
+// synchronous *Lexer, or the channel-backed pipelineLexer in pipeline.go.
+type tokenSource interface {
+	NextToken() (Token, error)
+}
+
 // Parser builds data structures from tokens
 type Parser struct {
-	lexer    *Lexer
-	curToken Token
+	lexer         tokenSource
+	curToken      Token
+	curTokenStale bool // set when the last advance() failed, so p.curToken is left over from before it
+	opts          Options
+	errs          ErrorList     // collected when Options.CollectErrors is set
+	path          []interface{} // object keys / array indices enclosing curToken, outermost first
 }
 
+// NewParser returns a strict, RFC 8259 Parser over input.
 func NewParser(input string) (*Parser, error) {
-	p := &Parser{lexer: NewLexer(input)}
-	tok, err := p.lexer.NextToken()
+	return newParser(newLexerFromString(input, Options{}), Options{})
+}
+
+// NewParserWithOptions returns a Parser over input with the relaxed
+// dialect extensions in opts enabled.
+func NewParserWithOptions(input string, opts Options) (*Parser, error) {
+	return newParser(newLexerFromString(input, opts), opts)
+}
+
+func newParser(lexer *Lexer, opts Options) (*Parser, error) {
+	return newParserFromSource(lexer, opts)
+}
+
+// newParserFromSource builds a Parser over any tokenSource, which lets
+// PipelineParser (pipeline.go) reuse this package's parsing logic on top
+// of its own channel-backed lexer instead of duplicating it.
+func newParserFromSource(lexer tokenSource, opts Options) (*Parser, error) {
+	p := &Parser{lexer: lexer, opts: opts}
+	tok, err := lexer.NextToken()
 	if err != nil {
 		return nil, err
 	}
@@ -236,14 +400,36 @@ func NewParser(input string) (*Parser, error) {
 func (p *Parser) advance() error {
 	tok, err := p.lexer.NextToken()
 	if err != nil {
+		p.curTokenStale = true
+		// Lexer errors are already *ParseError (see Lexer.errorf) but
+		// the lexer has no notion of p.path, so tag it here.
+		if pe, ok := err.(*ParseError); ok && pe.Path == nil {
+			pe.Path = append([]interface{}(nil), p.path...)
+		}
 		return err
 	}
 	p.curToken = tok
+	p.curTokenStale = false
 	return nil
 }
 
+// Parse builds the full value tree from the token stream. When
+// Options.CollectErrors is set and one or more recoverable syntax errors
+// were found, Parse returns its best-effort partial value alongside an
+// ErrorList describing every error found.
 func (p *Parser) Parse() (interface{}, error) {
-	return p.parseValue()
+	val, err := p.parseValue()
+	if err != nil {
+		if !p.opts.CollectErrors {
+			return nil, err
+		}
+		p.errs = append(p.errs, asParseError(err))
+		return val, p.errs
+	}
+	if len(p.errs) > 0 {
+		return val, p.errs
+	}
+	return val, nil
 }
 
 func (p *Parser) parseValue() (interface{}, error) {
@@ -254,99 +440,285 @@ func (p *Parser) parseValue() (interface{}, error) {
 		return p.parseArray()
 	case TokenString:
 		val := p.curToken.Value
-		p.advance()
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
 		return val, nil
 	case TokenNumber:
-		val, err := strconv.ParseFloat(p.curToken.Value, 64)
+		val, err := parseNumberLiteral(p.curToken.Value)
 		if err != nil {
+			return nil, p.errorf("%s", err)
+		}
+		if err := p.advance(); err != nil {
 			return nil, err
 		}
-		p.advance()
 		return val, nil
 	case TokenTrue:
-		p.advance()
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
 		return true, nil
 	case TokenFalse:
-		p.advance()
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
 		return false, nil
 	case TokenNull:
-		p.advance()
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
 		return nil, nil
 	default:
-		return nil, fmt.Errorf("unexpected token: %v", p.curToken.Type)
+		return nil, p.errorf("unexpected token: %v", p.curToken.Type)
 	}
 }
 
 func (p *Parser) parseObject() (map[string]interface{}, error) {
 	obj := make(map[string]interface{})
 
-	p.advance() // skip {
+	// pathBase is the path depth on entry; every key parsed at this
+	// level is pushed on top of it so errorf can report which key an
+	// error occurred under (colon, value or delimiter), and it is
+	// restored on every exit so the path doesn't leak into the caller.
+	pathBase := len(p.path)
+	defer func() { p.path = p.path[:pathBase] }()
+
+	if err := p.advance(); err != nil { // skip {
+		return nil, err
+	}
 
 	if p.curToken.Type == TokenRightBrace {
-		p.advance()
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
 		return obj, nil
 	}
 
 	for {
-		if p.curToken.Type != TokenString {
-			return nil, fmt.Errorf("expected string key, got %v", p.curToken.Type)
+		key, err := p.parseKey()
+		if err != nil {
+			if rerr := p.recoverOrFail(err); rerr != nil {
+				return obj, rerr
+			}
+			done, rerr := p.afterObjectRecovery()
+			if rerr != nil {
+				return obj, rerr
+			}
+			if done {
+				return obj, nil
+			}
+			continue
 		}
-
-		key := p.curToken.Value
-		p.advance()
+		p.path = append(p.path[:pathBase], key)
 
 		if p.curToken.Type != TokenColon {
-			return nil, fmt.Errorf("expected colon after key")
+			if rerr := p.recoverOrFail(p.errorf("expected colon after key")); rerr != nil {
+				return obj, rerr
+			}
+			done, rerr := p.afterObjectRecovery()
+			if rerr != nil {
+				return obj, rerr
+			}
+			if done {
+				return obj, nil
+			}
+			continue
+		}
+		if err := p.advance(); err != nil {
+			if rerr := p.recoverOrFail(err); rerr != nil {
+				return obj, rerr
+			}
+			done, rerr := p.afterObjectRecovery()
+			if rerr != nil {
+				return obj, rerr
+			}
+			if done {
+				return obj, nil
+			}
+			continue
 		}
-		p.advance()
 
 		val, err := p.parseValue()
 		if err != nil {
-			return nil, err
+			if rerr := p.recoverOrFail(err); rerr != nil {
+				return obj, rerr
+			}
+			done, rerr := p.afterObjectRecovery()
+			if rerr != nil {
+				return obj, rerr
+			}
+			if done {
+				return obj, nil
+			}
+			continue
 		}
-
 		obj[key] = val
 
 		if p.curToken.Type == TokenRightBrace {
-			p.advance()
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
 			return obj, nil
 		}
 
 		if p.curToken.Type != TokenComma {
-			return nil, fmt.Errorf("expected comma or closing brace")
+			if rerr := p.recoverOrFail(p.errorf("expected comma or closing brace")); rerr != nil {
+				return obj, rerr
+			}
+			done, rerr := p.afterObjectRecovery()
+			if rerr != nil {
+				return obj, rerr
+			}
+			if done {
+				return obj, nil
+			}
+			continue
+		}
+		if err := p.advance(); err != nil {
+			if rerr := p.recoverOrFail(err); rerr != nil {
+				return obj, rerr
+			}
+			done, rerr := p.afterObjectRecovery()
+			if rerr != nil {
+				return obj, rerr
+			}
+			if done {
+				return obj, nil
+			}
+			continue
+		}
+
+		if p.opts.AllowTrailingCommas && p.curToken.Type == TokenRightBrace {
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			return obj, nil
 		}
-		p.advance()
+	}
+}
+
+// parseKey reads an object key: a string, or (with Options.AllowUnquotedKeys)
+// a bare identifier.
+func (p *Parser) parseKey() (string, error) {
+	switch {
+	case p.curToken.Type == TokenString:
+		key := p.curToken.Value
+		if err := p.advance(); err != nil {
+			return "", err
+		}
+		return key, nil
+	case p.opts.AllowUnquotedKeys && p.curToken.Type == TokenIdent:
+		key := p.curToken.Value
+		if err := p.advance(); err != nil {
+			return "", err
+		}
+		return key, nil
+	default:
+		return "", p.errorf("expected string key, got %v", p.curToken.Type)
 	}
 }
 
 func (p *Parser) parseArray() ([]interface{}, error) {
 	arr := []interface{}{}
 
-	p.advance() // skip [
+	// pathBase is the path depth on entry; see parseObject's pathBase
+	// for why it's tracked and restored via defer.
+	pathBase := len(p.path)
+	defer func() { p.path = p.path[:pathBase] }()
+
+	if err := p.advance(); err != nil { // skip [
+		return nil, err
+	}
 
 	if p.curToken.Type == TokenRightBracket {
-		p.advance()
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
 		return arr, nil
 	}
 
-	for {
+	for idx := 0; ; idx++ {
+		p.path = append(p.path[:pathBase], idx)
 		val, err := p.parseValue()
 		if err != nil {
-			return nil, err
+			if rerr := p.recoverOrFail(err); rerr != nil {
+				return arr, rerr
+			}
+			done, rerr := p.afterArrayRecovery()
+			if rerr != nil {
+				return arr, rerr
+			}
+			if done {
+				return arr, nil
+			}
+			continue
 		}
 
 		arr = append(arr, val)
 
 		if p.curToken.Type == TokenRightBracket {
-			p.advance()
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
 			return arr, nil
 		}
 
 		if p.curToken.Type != TokenComma {
-			return nil, fmt.Errorf("expected comma or closing bracket")
+			if rerr := p.recoverOrFail(p.errorf("expected comma or closing bracket")); rerr != nil {
+				return arr, rerr
+			}
+			done, rerr := p.afterArrayRecovery()
+			if rerr != nil {
+				return arr, rerr
+			}
+			if done {
+				return arr, nil
+			}
+			continue
+		}
+		if err := p.advance(); err != nil {
+			if rerr := p.recoverOrFail(err); rerr != nil {
+				return arr, rerr
+			}
+			done, rerr := p.afterArrayRecovery()
+			if rerr != nil {
+				return arr, rerr
+			}
+			if done {
+				return arr, nil
+			}
+			continue
 		}
-		p.advance()
+
+		if p.opts.AllowTrailingCommas && p.curToken.Type == TokenRightBracket {
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			return arr, nil
+		}
+	}
+}
+
+// parseNumberLiteral converts a lexed number token into a float64,
+// handling the relaxed-dialect forms (hex, NaN, +/-Infinity) alongside
+// plain JSON numbers.
+func parseNumberLiteral(lit string) (float64, error) {
+	switch lit {
+	case "NaN":
+		return math.NaN(), nil
+	case "Infinity":
+		return math.Inf(1), nil
+	case "-Infinity":
+		return math.Inf(-1), nil
+	}
+	if unsigned := strings.TrimPrefix(lit, "-"); strings.HasPrefix(unsigned, "0x") || strings.HasPrefix(unsigned, "0X") {
+		n, err := strconv.ParseInt(lit, 0, 64)
+		if err != nil {
+			return 0, err
+		}
+		return float64(n), nil
 	}
+	return strconv.ParseFloat(lit, 64)
 }
 
 // Public API
@@ -358,23 +730,12 @@ func Parse(input string) (interface{}, error) {
 	return parser.Parse()
 }
 
-// Example usage
-func main() {
-	testCases := []string{
-		`{"name": "John", "age": 30, "active": true}`,
-		`[1, 2, 3, "hello", null, false]`,
-		`{"user": {"name": "Alice", "scores": [95, 87, 92]}}`,
-		`{"unicode": "Hello \u0057orld"}`,
-		`{"number": -123.45e-6}`,
-	}
-
-	for i, tc := range testCases {
-		fmt.Printf("\nTest case %d: %s\n", i+1, tc)
-		result, err := Parse(tc)
-		if err != nil {
-			fmt.Printf("Error: %v\n", err)
-		} else {
-			fmt.Printf("Result: %+v\n", result)
-		}
+// ParseWithOptions parses input, allowing the relaxed dialect extensions
+// in opts (see Options).
+func ParseWithOptions(input string, opts Options) (interface{}, error) {
+	parser, err := NewParserWithOptions(input, opts)
+	if err != nil {
+		return nil, err
 	}
+	return parser.Parse()
 }
@@ -1,4 +1,4 @@
-package main
+package smolparser
 
 import (
 	"reflect"
@@ -16,6 +16,7 @@ func TestParseString(t *testing.T) {
 		{`"hello\tworld"`, "hello\tworld"},
 		{`"quote: \"test\""`, `quote: "test"`},
 		{`"unicode: \u0048\u0065\u006C\u006C\u006F"`, "unicode: Hello"},
+		{`"héllo, 世界"`, "héllo, 世界"},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,38 @@
+package smolparser
+
+// Options toggles relaxed-JSON dialect extensions that the strict,
+// RFC 8259 parser (Parse, NewParser) rejects by default. Pass an Options
+// value to NewParserWithOptions/ParseWithOptions to parse real-world
+// "JSON-ish" config formats such as tsconfig.json or VS Code settings
+// without a fork of the parser.
+type Options struct {
+	// AllowComments permits `//` and `/* */` comments anywhere whitespace
+	// is allowed (JSONC).
+	AllowComments bool
+
+	// AllowTrailingCommas permits a trailing comma before the closing
+	// `}` or `]` of an object or array.
+	AllowTrailingCommas bool
+
+	// AllowSingleQuotes permits strings delimited by '...' in addition
+	// to "...".
+	AllowSingleQuotes bool
+
+	// AllowUnquotedKeys permits bare identifier object keys, e.g.
+	// `{foo: 1}` (JSON5).
+	AllowUnquotedKeys bool
+
+	// AllowHexNumbers permits hexadecimal integer literals such as
+	// `0xFF` (JSON5).
+	AllowHexNumbers bool
+
+	// AllowNaNInf permits the bare literals NaN, Infinity and
+	// -Infinity (JSON5).
+	AllowNaNInf bool
+
+	// CollectErrors enables recovery mode: on a syntax error inside an
+	// object or array, the parser discards tokens until the next ',',
+	// '}' or ']' and continues, instead of aborting. Parse then returns
+	// its best-effort value alongside an ErrorList of everything found.
+	CollectErrors bool
+}
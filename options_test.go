@@ -0,0 +1,108 @@
+package smolparser
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestParseWithOptionsRelaxedDialect(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		opts     Options
+		expected interface{}
+	}{
+		{
+			"line comment",
+			"{\"a\": 1 // trailing comment\n}",
+			Options{AllowComments: true},
+			map[string]interface{}{"a": 1.0},
+		},
+		{
+			"block comment",
+			`{/* leading */ "a": 1}`,
+			Options{AllowComments: true},
+			map[string]interface{}{"a": 1.0},
+		},
+		{
+			"trailing comma in object",
+			`{"a": 1,}`,
+			Options{AllowTrailingCommas: true},
+			map[string]interface{}{"a": 1.0},
+		},
+		{
+			"trailing comma in array",
+			`[1, 2,]`,
+			Options{AllowTrailingCommas: true},
+			[]interface{}{1.0, 2.0},
+		},
+		{
+			"single quoted string",
+			`{'a': 'b'}`,
+			Options{AllowSingleQuotes: true},
+			map[string]interface{}{"a": "b"},
+		},
+		{
+			"unquoted keys",
+			`{a: 1, b: 2}`,
+			Options{AllowUnquotedKeys: true},
+			map[string]interface{}{"a": 1.0, "b": 2.0},
+		},
+		{
+			"hex number",
+			`{"a": 0xFF}`,
+			Options{AllowHexNumbers: true},
+			map[string]interface{}{"a": 255.0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseWithOptions(tt.input, tt.opts)
+			if err != nil {
+				t.Fatalf("ParseWithOptions(%q) error: %v", tt.input, err)
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("ParseWithOptions(%q) = %v, want %v", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseWithOptionsNaNInf(t *testing.T) {
+	opts := Options{AllowNaNInf: true}
+
+	got, err := ParseWithOptions(`[NaN, Infinity, -Infinity]`, opts)
+	if err != nil {
+		t.Fatalf("ParseWithOptions error: %v", err)
+	}
+	arr, ok := got.([]interface{})
+	if !ok || len(arr) != 3 {
+		t.Fatalf("ParseWithOptions = %v, want 3-element array", got)
+	}
+	if !math.IsNaN(arr[0].(float64)) {
+		t.Errorf("arr[0] = %v, want NaN", arr[0])
+	}
+	if arr[1].(float64) != math.Inf(1) {
+		t.Errorf("arr[1] = %v, want +Inf", arr[1])
+	}
+	if arr[2].(float64) != math.Inf(-1) {
+		t.Errorf("arr[2] = %v, want -Inf", arr[2])
+	}
+}
+
+func TestParseStrictRejectsExtensions(t *testing.T) {
+	tests := []string{
+		`{"a": 1,}`,
+		`{'a': 'b'}`,
+		`{a: 1}`,
+		`{"a": 0xFF}`,
+		`{"a": 1 // comment\n}`,
+	}
+	for _, input := range tests {
+		if _, err := Parse(input); err == nil {
+			t.Errorf("Parse(%q) should have returned an error in strict mode", input)
+		}
+	}
+}
@@ -0,0 +1,298 @@
+package smolparser
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// This file implements an alternative, concurrent lexer/parser pipeline
+// following Rob Pike's "Lexical Scanning in Go" pattern: the lexer runs in
+// its own goroutine as a chain of state functions and emits tokens onto a
+// buffered channel, instead of being pulled synchronously via
+// Lexer.NextToken. See pipeline_test.go for a benchmark comparing the two
+// approaches.
+
+const pipelineTokenBuffer = 64
+
+// stateFn represents a lexer state as a function that scans the next
+// token (if any) and returns the state to run next, or nil to stop.
+type stateFn func(*pipelineLexer) stateFn
+
+// pipelineLexer tokenizes input on its own goroutine, emitting onto
+// tokens. Callers that abandon a parse partway through must call Cancel
+// so the goroutine doesn't leak.
+type pipelineLexer struct {
+	input string
+	start int
+	pos   int
+
+	tokens chan Token
+	done   chan struct{}
+	once   sync.Once
+
+	err error
+}
+
+func newPipelineLexer(input string) *pipelineLexer {
+	l := &pipelineLexer{
+		input:  input,
+		tokens: make(chan Token, pipelineTokenBuffer),
+		done:   make(chan struct{}),
+	}
+	go l.run()
+	return l
+}
+
+// Cancel stops the lexer goroutine. It is safe to call multiple times and
+// safe to call after the lexer has already finished.
+func (l *pipelineLexer) Cancel() {
+	l.once.Do(func() { close(l.done) })
+}
+
+func (l *pipelineLexer) run() {
+	defer close(l.tokens)
+	for state := lexValue; state != nil; {
+		select {
+		case <-l.done:
+			return
+		default:
+		}
+		state = state(l)
+	}
+}
+
+// emit sends the lexeme between start and pos as a token of type t.
+func (l *pipelineLexer) emit(t TokenType) {
+	l.emitToken(Token{Type: t, Value: l.input[l.start:l.pos], Pos: l.start})
+}
+
+func (l *pipelineLexer) emitToken(tok Token) {
+	select {
+	case l.tokens <- tok:
+	case <-l.done:
+	}
+	l.start = l.pos
+}
+
+// emitError records err and terminates the state machine: the next
+// receive on tokens will see the channel close, at which point callers
+// should consult (*pipelineLexer).err.
+func (l *pipelineLexer) emitError(format string, args ...interface{}) stateFn {
+	l.err = fmt.Errorf(format, args...)
+	return nil
+}
+
+func (l *pipelineLexer) peek() byte {
+	if l.pos >= len(l.input) {
+		return 0
+	}
+	return l.input[l.pos]
+}
+
+func lexValue(l *pipelineLexer) stateFn {
+	for {
+		ch := l.peek()
+		if ch == ' ' || ch == '\t' || ch == '\n' || ch == '\r' {
+			l.pos++
+			continue
+		}
+		break
+	}
+	l.start = l.pos
+
+	ch := l.peek()
+	switch {
+	case ch == 0:
+		l.emit(TokenEOF)
+		return nil
+	case ch == '{':
+		l.pos++
+		l.emit(TokenLeftBrace)
+		return lexValue
+	case ch == '}':
+		l.pos++
+		l.emit(TokenRightBrace)
+		return lexValue
+	case ch == '[':
+		l.pos++
+		l.emit(TokenLeftBracket)
+		return lexValue
+	case ch == ']':
+		l.pos++
+		l.emit(TokenRightBracket)
+		return lexValue
+	case ch == ':':
+		l.pos++
+		l.emit(TokenColon)
+		return lexValue
+	case ch == ',':
+		l.pos++
+		l.emit(TokenComma)
+		return lexValue
+	case ch == '"':
+		return lexString
+	case ch == '-' || unicode.IsDigit(rune(ch)):
+		return lexNumber
+	case unicode.IsLetter(rune(ch)):
+		return lexIdent
+	default:
+		return l.emitError("unexpected character: %c", ch)
+	}
+}
+
+func lexString(l *pipelineLexer) stateFn {
+	start := l.pos
+	l.pos++ // opening quote
+
+	var sb strings.Builder
+	for {
+		if l.pos >= len(l.input) {
+			return l.emitError("unterminated string")
+		}
+		ch := l.input[l.pos]
+		if ch == '"' {
+			l.pos++
+			break
+		}
+		if ch != '\\' {
+			sb.WriteByte(ch)
+			l.pos++
+			continue
+		}
+
+		l.pos++ // backslash
+		if l.pos >= len(l.input) {
+			return l.emitError("unterminated string")
+		}
+		esc := l.input[l.pos]
+		switch esc {
+		case '"', '\\', '/':
+			sb.WriteByte(esc)
+		case 'b':
+			sb.WriteByte('\b')
+		case 'f':
+			sb.WriteByte('\f')
+		case 'n':
+			sb.WriteByte('\n')
+		case 'r':
+			sb.WriteByte('\r')
+		case 't':
+			sb.WriteByte('\t')
+		case 'u':
+			if l.pos+4 >= len(l.input) {
+				return l.emitError("invalid unicode escape")
+			}
+			hex := l.input[l.pos+1 : l.pos+5]
+			val, err := strconv.ParseInt(hex, 16, 32)
+			if err != nil {
+				return l.emitError("invalid unicode escape: %s", hex)
+			}
+			sb.WriteRune(rune(val))
+			l.pos += 4
+		default:
+			return l.emitError("invalid escape sequence: \\%c", esc)
+		}
+		l.pos++
+	}
+
+	l.emitToken(Token{Type: TokenString, Value: sb.String(), Pos: start})
+	return lexValue
+}
+
+func lexNumber(l *pipelineLexer) stateFn {
+	if l.peek() == '-' {
+		l.pos++
+	}
+
+	if l.peek() == '0' {
+		l.pos++
+	} else {
+		for unicode.IsDigit(rune(l.peek())) {
+			l.pos++
+		}
+	}
+
+	if l.peek() == '.' {
+		l.pos++
+		for unicode.IsDigit(rune(l.peek())) {
+			l.pos++
+		}
+	}
+
+	if ch := l.peek(); ch == 'e' || ch == 'E' {
+		l.pos++
+		if ch := l.peek(); ch == '+' || ch == '-' {
+			l.pos++
+		}
+		for unicode.IsDigit(rune(l.peek())) {
+			l.pos++
+		}
+	}
+
+	l.emit(TokenNumber)
+	return lexValue
+}
+
+func lexIdent(l *pipelineLexer) stateFn {
+	start := l.pos
+	for unicode.IsLetter(rune(l.peek())) {
+		l.pos++
+	}
+	lit := l.input[start:l.pos]
+
+	var t TokenType
+	switch lit {
+	case "true":
+		t = TokenTrue
+	case "false":
+		t = TokenFalse
+	case "null":
+		t = TokenNull
+	default:
+		return l.emitError("unexpected identifier: %s", lit)
+	}
+	l.emitToken(Token{Type: t, Pos: start})
+	return lexValue
+}
+
+// NextToken implements tokenSource by reading the next token off the
+// channel the lexer goroutine is emitting onto, letting PipelineParser
+// reuse Parser's parsing logic instead of duplicating it.
+func (l *pipelineLexer) NextToken() (Token, error) {
+	tok, ok := <-l.tokens
+	if !ok {
+		if l.err != nil {
+			return Token{}, l.err
+		}
+		return Token{}, fmt.Errorf("smolparser: token stream closed unexpectedly")
+	}
+	return tok, nil
+}
+
+// PipelineParser is a Parser fed by a pipelineLexer running on its own
+// goroutine, rather than pulling tokens synchronously like Parser does
+// by default.
+type PipelineParser struct {
+	*Parser
+	lexer *pipelineLexer
+}
+
+// NewPipelineParser starts the lexer goroutine and primes the parser with
+// the first token.
+func NewPipelineParser(input string) (*PipelineParser, error) {
+	l := newPipelineLexer(input)
+	p, err := newParserFromSource(l, Options{})
+	if err != nil {
+		return nil, err
+	}
+	return &PipelineParser{Parser: p, lexer: l}, nil
+}
+
+// Cancel aborts the underlying lexer goroutine. Call it if you stop
+// draining Parse partway through.
+func (p *PipelineParser) Cancel() {
+	p.lexer.Cancel()
+}
@@ -0,0 +1,82 @@
+package smolparser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPipelineParserMatchesParser(t *testing.T) {
+	inputs := []string{
+		`{"name": "John", "age": 30, "active": true}`,
+		`[1, 2, 3, "hello", null, false]`,
+		`{"user": {"name": "Alice", "scores": [95, 87, 92]}}`,
+	}
+
+	for _, input := range inputs {
+		want, err := Parse(input)
+		if err != nil {
+			t.Fatalf("Parse(%q) error: %v", input, err)
+		}
+
+		pp, err := NewPipelineParser(input)
+		if err != nil {
+			t.Fatalf("NewPipelineParser(%q) error: %v", input, err)
+		}
+		got, err := pp.Parse()
+		if err != nil {
+			t.Fatalf("PipelineParser.Parse(%q) error: %v", input, err)
+		}
+
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("PipelineParser.Parse(%q) = %v, want %v", input, got, want)
+		}
+	}
+}
+
+func TestPipelineParserCancel(t *testing.T) {
+	pp, err := NewPipelineParser(`[1, 2, 3, 4, 5]`)
+	if err != nil {
+		t.Fatalf("NewPipelineParser error: %v", err)
+	}
+	pp.Cancel()
+}
+
+func largeJSONArray(n int) string {
+	var b strings.Builder
+	b.WriteByte('[')
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(`{"id":`)
+		b.WriteString("1")
+		b.WriteString(`,"name":"item","active":true}`)
+	}
+	b.WriteByte(']')
+	return b.String()
+}
+
+func BenchmarkParsePullModel(b *testing.B) {
+	input := largeJSONArray(1000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := Parse(input); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkParsePipeline(b *testing.B) {
+	input := largeJSONArray(1000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		pp, err := NewPipelineParser(input)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if _, err := pp.Parse(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
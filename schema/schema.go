@@ -0,0 +1,288 @@
+// Package schema implements a minimal JSON-Schema-lite subset —
+// type, required, enum, minimum/maximum, minLength/maxLength and
+// pattern — for validating the interface{} trees produced by
+// smolparser.Parse, without pulling in a separate JSON Schema library.
+package schema
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+
+	smolparser "smol-go/smol-parser"
+)
+
+// Schema is a compiled JSON-Schema-lite document.
+type Schema struct {
+	typ        string
+	required   []string
+	properties map[string]*Schema
+	items      *Schema
+	enum       []interface{}
+	minimum    *float64
+	maximum    *float64
+	minLength  *int
+	maxLength  *int
+	pattern    *regexp.Regexp
+}
+
+// Compile parses schemaJSON (itself a JSON document) into a *Schema.
+func Compile(schemaJSON string) (*Schema, error) {
+	parsed, err := smolparser.Parse(schemaJSON)
+	if err != nil {
+		return nil, fmt.Errorf("schema: %w", err)
+	}
+	root, ok := parsed.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("schema: root must be a JSON object, got %T", parsed)
+	}
+	return compileNode(root)
+}
+
+func compileNode(m map[string]interface{}) (*Schema, error) {
+	s := &Schema{}
+
+	if t, ok := m["type"]; ok {
+		str, ok := t.(string)
+		if !ok {
+			return nil, fmt.Errorf("schema: \"type\" must be a string")
+		}
+		s.typ = str
+	}
+
+	if req, ok := m["required"]; ok {
+		items, ok := req.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("schema: \"required\" must be an array of strings")
+		}
+		for _, item := range items {
+			name, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("schema: \"required\" must be an array of strings")
+			}
+			s.required = append(s.required, name)
+		}
+	}
+
+	if props, ok := m["properties"]; ok {
+		propsMap, ok := props.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("schema: \"properties\" must be an object")
+		}
+		s.properties = make(map[string]*Schema, len(propsMap))
+		for name, raw := range propsMap {
+			propMap, ok := raw.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("schema: properties.%s must be an object", name)
+			}
+			child, err := compileNode(propMap)
+			if err != nil {
+				return nil, fmt.Errorf("schema: properties.%s: %w", name, err)
+			}
+			s.properties[name] = child
+		}
+	}
+
+	if items, ok := m["items"]; ok {
+		itemsMap, ok := items.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("schema: \"items\" must be an object")
+		}
+		child, err := compileNode(itemsMap)
+		if err != nil {
+			return nil, fmt.Errorf("schema: items: %w", err)
+		}
+		s.items = child
+	}
+
+	if enum, ok := m["enum"]; ok {
+		items, ok := enum.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("schema: \"enum\" must be an array")
+		}
+		s.enum = items
+	}
+
+	if f, ok := numberField(m, "minimum"); ok {
+		s.minimum = &f
+	}
+	if f, ok := numberField(m, "maximum"); ok {
+		s.maximum = &f
+	}
+	if n, ok := intField(m, "minLength"); ok {
+		s.minLength = &n
+	}
+	if n, ok := intField(m, "maxLength"); ok {
+		s.maxLength = &n
+	}
+
+	if pat, ok := m["pattern"]; ok {
+		str, ok := pat.(string)
+		if !ok {
+			return nil, fmt.Errorf("schema: \"pattern\" must be a string")
+		}
+		re, err := regexp.Compile(str)
+		if err != nil {
+			return nil, fmt.Errorf("schema: invalid pattern %q: %w", str, err)
+		}
+		s.pattern = re
+	}
+
+	return s, nil
+}
+
+func numberField(m map[string]interface{}, key string) (float64, bool) {
+	raw, ok := m[key]
+	if !ok {
+		return 0, false
+	}
+	n, ok := raw.(float64)
+	return n, ok
+}
+
+func intField(m map[string]interface{}, key string) (int, bool) {
+	n, ok := numberField(m, key)
+	if !ok {
+		return 0, false
+	}
+	return int(n), true
+}
+
+// Validate checks v (typically a value returned by smolparser.Parse)
+// against the schema and returns every violation found. A nil/empty
+// result means v is valid.
+func (s *Schema) Validate(v interface{}) []smolparser.ParseError {
+	var errs []smolparser.ParseError
+	s.validate(v, nil, &errs)
+	return errs
+}
+
+func (s *Schema) validate(v interface{}, path []interface{}, errs *[]smolparser.ParseError) {
+	if !s.checkType(v) {
+		*errs = append(*errs, smolparser.ParseError{
+			Msg:  fmt.Sprintf("expected type %q, got %s", s.typ, typeName(v)),
+			Path: path,
+		})
+		return
+	}
+
+	switch s.typ {
+	case "object":
+		m, _ := v.(map[string]interface{})
+		for _, name := range s.required {
+			if _, ok := m[name]; !ok {
+				*errs = append(*errs, smolparser.ParseError{
+					Msg:  fmt.Sprintf("missing required property %q", name),
+					Path: path,
+				})
+			}
+		}
+		for name, child := range s.properties {
+			val, ok := m[name]
+			if !ok {
+				continue
+			}
+			child.validate(val, appendPath(path, name), errs)
+		}
+
+	case "array":
+		arr, _ := v.([]interface{})
+		if s.items != nil {
+			for i, item := range arr {
+				s.items.validate(item, appendPath(path, i), errs)
+			}
+		}
+	}
+
+	if len(s.enum) > 0 && !enumContains(s.enum, v) {
+		*errs = append(*errs, smolparser.ParseError{Msg: "value is not one of the allowed enum values", Path: path})
+	}
+
+	if n, ok := v.(float64); ok {
+		if s.minimum != nil && n < *s.minimum {
+			*errs = append(*errs, smolparser.ParseError{Msg: fmt.Sprintf("%v is less than minimum %v", n, *s.minimum), Path: path})
+		}
+		if s.maximum != nil && n > *s.maximum {
+			*errs = append(*errs, smolparser.ParseError{Msg: fmt.Sprintf("%v is greater than maximum %v", n, *s.maximum), Path: path})
+		}
+	}
+
+	if str, ok := v.(string); ok {
+		if s.minLength != nil && len(str) < *s.minLength {
+			*errs = append(*errs, smolparser.ParseError{Msg: fmt.Sprintf("length %d is less than minLength %d", len(str), *s.minLength), Path: path})
+		}
+		if s.maxLength != nil && len(str) > *s.maxLength {
+			*errs = append(*errs, smolparser.ParseError{Msg: fmt.Sprintf("length %d is greater than maxLength %d", len(str), *s.maxLength), Path: path})
+		}
+		if s.pattern != nil && !s.pattern.MatchString(str) {
+			*errs = append(*errs, smolparser.ParseError{Msg: fmt.Sprintf("value does not match pattern %q", s.pattern.String()), Path: path})
+		}
+	}
+}
+
+func (s *Schema) checkType(v interface{}) bool {
+	switch s.typ {
+	case "":
+		return true
+	case "object":
+		_, ok := v.(map[string]interface{})
+		return ok
+	case "array":
+		_, ok := v.([]interface{})
+		return ok
+	case "string":
+		_, ok := v.(string)
+		return ok
+	case "boolean":
+		_, ok := v.(bool)
+		return ok
+	case "number":
+		_, ok := v.(float64)
+		return ok
+	case "integer":
+		n, ok := v.(float64)
+		return ok && n == math.Trunc(n)
+	case "null":
+		return v == nil
+	default:
+		return true
+	}
+}
+
+func typeName(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
+
+func enumContains(enum []interface{}, v interface{}) bool {
+	for _, e := range enum {
+		if reflect.DeepEqual(e, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// appendPath returns a new path with seg appended, without risking
+// aliasing the caller's backing array across sibling validate calls.
+func appendPath(path []interface{}, seg interface{}) []interface{} {
+	out := make([]interface{}, len(path)+1)
+	copy(out, path)
+	out[len(path)] = seg
+	return out
+}
@@ -0,0 +1,127 @@
+package schema
+
+import (
+	"testing"
+
+	smolparser "smol-go/smol-parser"
+)
+
+func TestValidateValid(t *testing.T) {
+	s, err := Compile(`{
+		"type": "object",
+		"required": ["name", "age"],
+		"properties": {
+			"name": {"type": "string", "minLength": 1},
+			"age": {"type": "integer", "minimum": 0, "maximum": 150},
+			"role": {"type": "string", "enum": ["admin", "member"]}
+		}
+	}`)
+	if err != nil {
+		t.Fatalf("Compile error: %v", err)
+	}
+
+	v, err := smolparser.Parse(`{"name": "Ada", "age": 36, "role": "admin"}`)
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+	if errs := s.Validate(v); len(errs) != 0 {
+		t.Errorf("Validate() = %v, want no errors", errs)
+	}
+}
+
+func TestValidateViolations(t *testing.T) {
+	tests := []struct {
+		name       string
+		schemaJSON string
+		input      string
+		wantCount  int
+	}{
+		{
+			"missing required property",
+			`{"type": "object", "required": ["name"]}`,
+			`{}`,
+			1,
+		},
+		{
+			"wrong type",
+			`{"type": "object", "properties": {"age": {"type": "integer"}}}`,
+			`{"age": "thirty"}`,
+			1,
+		},
+		{
+			"below minimum",
+			`{"type": "object", "properties": {"age": {"type": "number", "minimum": 18}}}`,
+			`{"age": 5}`,
+			1,
+		},
+		{
+			"above maximum",
+			`{"type": "object", "properties": {"age": {"type": "number", "maximum": 18}}}`,
+			`{"age": 99}`,
+			1,
+		},
+		{
+			"too short",
+			`{"type": "object", "properties": {"name": {"type": "string", "minLength": 3}}}`,
+			`{"name": "Al"}`,
+			1,
+		},
+		{
+			"too long",
+			`{"type": "object", "properties": {"name": {"type": "string", "maxLength": 3}}}`,
+			`{"name": "Alexandra"}`,
+			1,
+		},
+		{
+			"pattern mismatch",
+			`{"type": "object", "properties": {"code": {"type": "string", "pattern": "^[A-Z]{3}$"}}}`,
+			`{"code": "a1"}`,
+			1,
+		},
+		{
+			"not in enum",
+			`{"type": "object", "properties": {"role": {"enum": ["admin", "member"]}}}`,
+			`{"role": "superuser"}`,
+			1,
+		},
+		{
+			"array items validated",
+			`{"type": "array", "items": {"type": "integer", "minimum": 0}}`,
+			`[1, -2, 3, -4]`,
+			2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := Compile(tt.schemaJSON)
+			if err != nil {
+				t.Fatalf("Compile error: %v", err)
+			}
+			v, err := smolparser.Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse error: %v", err)
+			}
+			errs := s.Validate(v)
+			if len(errs) != tt.wantCount {
+				t.Errorf("Validate(%s) = %v, want %d error(s)", tt.input, errs, tt.wantCount)
+			}
+		})
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	tests := []string{
+		`{"type": 5}`,
+		`{"required": "name"}`,
+		`{"properties": "nope"}`,
+		`{"pattern": "("}`,
+		`not even json`,
+	}
+
+	for _, schemaJSON := range tests {
+		if _, err := Compile(schemaJSON); err == nil {
+			t.Errorf("Compile(%q) should have returned an error", schemaJSON)
+		}
+	}
+}
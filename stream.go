@@ -0,0 +1,357 @@
+package smolparser
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+)
+
+// EventType identifies the kind of Event produced by a StreamParser.
+type EventType int
+
+const (
+	StartObject EventType = iota
+	EndObject
+	StartArray
+	EndArray
+	Key
+	Value
+)
+
+// Event is one step of a streamed parse: the opening/closing of a
+// container, an object key, or a scalar value.
+type Event struct {
+	Type  EventType
+	Key   string
+	Value interface{}
+}
+
+type frameKind int
+
+const (
+	frameObject frameKind = iota
+	frameArray
+)
+
+type frame struct {
+	kind      frameKind
+	expectKey bool // frameObject: next token should be a key (or '}')
+	first     bool // no comma required before the next element/key
+}
+
+// StreamParser walks a JSON document one Event at a time instead of
+// building the whole tree, so large documents can be processed without
+// holding them fully in memory.
+type StreamParser struct {
+	lexer *Lexer
+	cur   Token
+	stack []frame
+	done  bool
+}
+
+// NewStreamParser creates a StreamParser reading from r.
+func NewStreamParser(r io.Reader) (*StreamParser, error) {
+	l := newLexerFromReader(r)
+	tok, err := l.NextToken()
+	if err != nil {
+		return nil, err
+	}
+	return &StreamParser{lexer: l, cur: tok}, nil
+}
+
+func (sp *StreamParser) advance() error {
+	tok, err := sp.lexer.NextToken()
+	if err != nil {
+		return err
+	}
+	sp.cur = tok
+	return nil
+}
+
+// Next returns the next event in the stream, or io.EOF once the document
+// has been fully consumed.
+func (sp *StreamParser) Next() (Event, error) {
+	if sp.done {
+		return Event{}, io.EOF
+	}
+
+	if len(sp.stack) == 0 {
+		if sp.cur.Type == TokenEOF {
+			sp.done = true
+			return Event{}, io.EOF
+		}
+		return sp.emitValue()
+	}
+
+	top := &sp.stack[len(sp.stack)-1]
+	switch top.kind {
+	case frameObject:
+		if top.expectKey {
+			if sp.cur.Type == TokenRightBrace {
+				if err := sp.advance(); err != nil {
+					return Event{}, err
+				}
+				sp.popFrame()
+				return Event{Type: EndObject}, nil
+			}
+			if !top.first {
+				if sp.cur.Type != TokenComma {
+					return Event{}, fmt.Errorf("smolparser: expected comma or closing brace")
+				}
+				if err := sp.advance(); err != nil {
+					return Event{}, err
+				}
+			}
+			if sp.cur.Type != TokenString {
+				return Event{}, fmt.Errorf("smolparser: expected string key, got %v", sp.cur.Type)
+			}
+			key := sp.cur.Value
+			if err := sp.advance(); err != nil {
+				return Event{}, err
+			}
+			if sp.cur.Type != TokenColon {
+				return Event{}, fmt.Errorf("smolparser: expected colon after key")
+			}
+			if err := sp.advance(); err != nil {
+				return Event{}, err
+			}
+			top.expectKey = false
+			top.first = false
+			return Event{Type: Key, Key: key}, nil
+		}
+		top.expectKey = true
+		return sp.emitValue()
+
+	default: // frameArray
+		if sp.cur.Type == TokenRightBracket {
+			if err := sp.advance(); err != nil {
+				return Event{}, err
+			}
+			sp.popFrame()
+			return Event{Type: EndArray}, nil
+		}
+		if !top.first {
+			if sp.cur.Type != TokenComma {
+				return Event{}, fmt.Errorf("smolparser: expected comma or closing bracket")
+			}
+			if err := sp.advance(); err != nil {
+				return Event{}, err
+			}
+		}
+		top.first = false
+		return sp.emitValue()
+	}
+}
+
+// emitValue emits the Event for whatever scalar or container opener sp.cur
+// currently points at, pushing a frame for containers.
+func (sp *StreamParser) emitValue() (Event, error) {
+	switch sp.cur.Type {
+	case TokenLeftBrace:
+		if err := sp.advance(); err != nil {
+			return Event{}, err
+		}
+		sp.stack = append(sp.stack, frame{kind: frameObject, expectKey: true, first: true})
+		return Event{Type: StartObject}, nil
+
+	case TokenLeftBracket:
+		if err := sp.advance(); err != nil {
+			return Event{}, err
+		}
+		sp.stack = append(sp.stack, frame{kind: frameArray, first: true})
+		return Event{Type: StartArray}, nil
+
+	case TokenString:
+		val := sp.cur.Value
+		if err := sp.advance(); err != nil {
+			return Event{}, err
+		}
+		return Event{Type: Value, Value: val}, nil
+
+	case TokenNumber:
+		n, err := strconv.ParseFloat(sp.cur.Value, 64)
+		if err != nil {
+			return Event{}, err
+		}
+		if err := sp.advance(); err != nil {
+			return Event{}, err
+		}
+		return Event{Type: Value, Value: n}, nil
+
+	case TokenTrue, TokenFalse:
+		v := sp.cur.Type == TokenTrue
+		if err := sp.advance(); err != nil {
+			return Event{}, err
+		}
+		return Event{Type: Value, Value: v}, nil
+
+	case TokenNull:
+		if err := sp.advance(); err != nil {
+			return Event{}, err
+		}
+		return Event{Type: Value, Value: nil}, nil
+
+	default:
+		return Event{}, fmt.Errorf("smolparser: unexpected token: %v", sp.cur.Type)
+	}
+}
+
+func (sp *StreamParser) popFrame() {
+	sp.stack = sp.stack[:len(sp.stack)-1]
+}
+
+// ReadValue reads the next complete value off the stream (a scalar, or an
+// entire object/array) and returns it in the same shape Parse produces.
+// It is the streaming counterpart of Parse for callers, such as
+// jsonpath.EvalStream, that want one value without driving Next/Key/
+// EndObject events by hand.
+func (sp *StreamParser) ReadValue() (interface{}, error) {
+	ev, err := sp.Next()
+	if err != nil {
+		return nil, err
+	}
+	return sp.readValue(ev)
+}
+
+// readValue consumes whatever further events are needed to turn first (an
+// already-read Event) into a complete interface{} value.
+func (sp *StreamParser) readValue(first Event) (interface{}, error) {
+	switch first.Type {
+	case Value:
+		return first.Value, nil
+
+	case StartObject:
+		obj := make(map[string]interface{})
+		for {
+			ev, err := sp.Next()
+			if err != nil {
+				return nil, err
+			}
+			if ev.Type == EndObject {
+				return obj, nil
+			}
+			if ev.Type != Key {
+				return nil, fmt.Errorf("smolparser: expected key event, got %v", ev.Type)
+			}
+			valEv, err := sp.Next()
+			if err != nil {
+				return nil, err
+			}
+			val, err := sp.readValue(valEv)
+			if err != nil {
+				return nil, err
+			}
+			obj[ev.Key] = val
+		}
+
+	case StartArray:
+		arr := []interface{}{}
+		for {
+			ev, err := sp.Next()
+			if err != nil {
+				return nil, err
+			}
+			if ev.Type == EndArray {
+				return arr, nil
+			}
+			val, err := sp.readValue(ev)
+			if err != nil {
+				return nil, err
+			}
+			arr = append(arr, val)
+		}
+
+	default:
+		return nil, fmt.Errorf("smolparser: unexpected event: %v", first.Type)
+	}
+}
+
+// ForEachInArray streams a top-level JSON array, invoking fn with each
+// element as it is decoded rather than materializing the whole array.
+func (sp *StreamParser) ForEachInArray(fn func(interface{}) error) error {
+	ev, err := sp.Next()
+	if err != nil {
+		return err
+	}
+	if ev.Type != StartArray {
+		return fmt.Errorf("smolparser: ForEachInArray requires a top-level array, got %v", ev.Type)
+	}
+
+	for {
+		ev, err := sp.Next()
+		if err != nil {
+			return err
+		}
+		if ev.Type == EndArray {
+			return nil
+		}
+		val, err := sp.readValue(ev)
+		if err != nil {
+			return err
+		}
+		if err := fn(val); err != nil {
+			return err
+		}
+	}
+}
+
+// DecodeStream streams r and binds the resulting value into v, which must
+// be a non-nil pointer, using reflection.
+func DecodeStream(r io.Reader, v interface{}) error {
+	sp, err := NewStreamParser(r)
+	if err != nil {
+		return err
+	}
+	ev, err := sp.Next()
+	if err != nil {
+		return err
+	}
+	val, err := sp.readValue(ev)
+	if err != nil {
+		return err
+	}
+	return assignReflect(reflect.ValueOf(v), val)
+}
+
+// assignReflect assigns the parsed value val into the value pointed to by
+// dst, which must be a pointer. It is a thin wrapper around decode.go's
+// decodeInto (with default DecodeOptions) so that DecodeStream supports
+// exactly the same struct tags, pointer fields, time.Time, and Validator
+// handling as Decode, instead of maintaining a second copy of that logic.
+func assignReflect(dst reflect.Value, val interface{}) error {
+	return decodeInto(dst, val, DecodeOptions{})
+}
+
+// lookupField matches a struct field name against a JSON object key,
+// case-insensitively.
+func lookupField(m map[string]interface{}, name string) (interface{}, bool) {
+	if v, ok := m[name]; ok {
+		return v, true
+	}
+	for k, v := range m {
+		if equalFold(k, name) {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+func equalFold(a, b string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := 0; i < len(a); i++ {
+		ca, cb := a[i], b[i]
+		if 'A' <= ca && ca <= 'Z' {
+			ca += 'a' - 'A'
+		}
+		if 'A' <= cb && cb <= 'Z' {
+			cb += 'a' - 'A'
+		}
+		if ca != cb {
+			return false
+		}
+	}
+	return true
+}
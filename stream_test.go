@@ -0,0 +1,91 @@
+package smolparser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStreamParserEvents(t *testing.T) {
+	sp, err := NewStreamParser(strings.NewReader(`{"name":"Alice","scores":[1,2]}`))
+	if err != nil {
+		t.Fatalf("NewStreamParser error: %v", err)
+	}
+
+	var types []EventType
+	for {
+		ev, err := sp.Next()
+		if err != nil {
+			break
+		}
+		types = append(types, ev.Type)
+	}
+
+	want := []EventType{
+		StartObject, Key, Value, Key, StartArray, Value, Value, EndArray, EndObject,
+	}
+	if len(types) != len(want) {
+		t.Fatalf("got %d events, want %d: %v", len(types), len(want), types)
+	}
+	for i := range want {
+		if types[i] != want[i] {
+			t.Errorf("event %d = %v, want %v", i, types[i], want[i])
+		}
+	}
+}
+
+func TestForEachInArray(t *testing.T) {
+	sp, err := NewStreamParser(strings.NewReader(`[1, "two", {"three": 3}]`))
+	if err != nil {
+		t.Fatalf("NewStreamParser error: %v", err)
+	}
+
+	var got []interface{}
+	err = sp.ForEachInArray(func(v interface{}) error {
+		got = append(got, v)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ForEachInArray error: %v", err)
+	}
+	if len(got) != 3 {
+		t.Fatalf("got %d elements, want 3: %v", len(got), got)
+	}
+}
+
+func TestDecodeStream(t *testing.T) {
+	type Person struct {
+		Name string
+		Age  float64
+	}
+
+	var p Person
+	err := DecodeStream(strings.NewReader(`{"name":"Bob","age":42}`), &p)
+	if err != nil {
+		t.Fatalf("DecodeStream error: %v", err)
+	}
+	if p.Name != "Bob" || p.Age != 42 {
+		t.Errorf("DecodeStream = %+v, want {Bob 42}", p)
+	}
+}
+
+// TestDecodeStreamHonorsJSONTag pins down that DecodeStream goes through
+// decodeInto (same as Decode), not a separate copy of the assignment
+// logic, so it understands "json" struct tags too.
+func TestDecodeStreamHonorsJSONTag(t *testing.T) {
+	type Person struct {
+		FullName string `json:"name"`
+		Secret   string `json:"-"`
+	}
+
+	var p Person
+	err := DecodeStream(strings.NewReader(`{"name":"Bob","Secret":"leaked"}`), &p)
+	if err != nil {
+		t.Fatalf("DecodeStream error: %v", err)
+	}
+	if p.FullName != "Bob" {
+		t.Errorf("FullName = %q, want %q", p.FullName, "Bob")
+	}
+	if p.Secret != "" {
+		t.Errorf("Secret = %q, want empty (json:\"-\" should be skipped)", p.Secret)
+	}
+}